@@ -2,6 +2,7 @@ package procfs
 
 import (
 	"bufio"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -25,6 +26,42 @@ type IPVSStats struct {
 	IncomingBytes uint64
 	// Total outgoing traffic.
 	OutgoingBytes uint64
+	// Current rate of new connections per second.
+	ConnectionRate uint64
+	// Current rate of incoming packets per second.
+	IncomingPacketRate uint64
+	// Current rate of outgoing packets per second.
+	OutgoingPacketRate uint64
+	// Current rate of incoming traffic in bytes per second.
+	IncomingByteRate uint64
+	// Current rate of outgoing traffic in bytes per second.
+	OutgoingByteRate uint64
+}
+
+// IPVSCPUStats holds the IPVS statistics of a single CPU, as exposed by the
+// kernel in `/proc/net/ip_vs_stats_percpu`.
+type IPVSCPUStats struct {
+	// The CPU the statistics belong to.
+	CPU int
+	// Count of connections handled by the CPU.
+	Connections uint64
+	// Incoming packages processed by the CPU.
+	IncomingPackets uint64
+	// Outgoing packages processed by the CPU.
+	OutgoingPackets uint64
+	// Incoming traffic processed by the CPU.
+	IncomingBytes uint64
+	// Outgoing traffic processed by the CPU.
+	OutgoingBytes uint64
+}
+
+// IPVSStatsPerCPU holds the IPVS statistics of all CPUs along with the
+// totals and current rates.
+type IPVSStatsPerCPU struct {
+	// The statistics of each possible CPU.
+	CPUs []IPVSCPUStats
+	// The totals and rates across all CPUs.
+	Total IPVSStats
 }
 
 // IPVSBackendStatus holds current metrics of one virtual / real address pair.
@@ -33,12 +70,18 @@ type IPVSBackendStatus struct {
 	LocalAddress net.IP
 	// The local (virtual) port.
 	LocalPort uint16
-	// The transport protocol (TCP, UDP).
+	// The transport protocol (TCP, UDP, SCTP), or FWM for firewall mark
+	// services.
 	Proto string
+	// The firewall mark of FWM services, which have no local address and
+	// port.
+	FWMark uint32
 	// The remote (real) IP address.
 	RemoteAddress net.IP
 	// The remote (real) port.
 	RemotePort uint16
+	// The forwarding method (Masq, Local, Tunnel, Route, Bypass).
+	ForwardMethod string
 	// The current number of active connections for this virtual/real address pair.
 	ActiveConn uint64
 	// The current number of inactive connections for this virtual/real address pair.
@@ -47,6 +90,52 @@ type IPVSBackendStatus struct {
 	Weight uint64
 }
 
+// IPVSService holds the configuration of one virtual service along with its
+// real servers. Traffic counters per service and real server aren't exposed
+// in `/proc/net/ip_vs`; the kernel only reports them via netlink.
+type IPVSService struct {
+	// The virtual IP address, nil for FWM services.
+	Address net.IP
+	// The virtual port, 0 for FWM services.
+	Port uint16
+	// The transport protocol (TCP, UDP, SCTP), or FWM for services matching
+	// packets by firewall mark.
+	Proto string
+	// The firewall mark of FWM services.
+	FWMark uint32
+	// The scheduler, e.g. rr, wlc or sh.
+	Scheduler string
+	// Whether each packet is scheduled separately (one-packet scheduling),
+	// only supported for UDP.
+	OnePacket bool
+	// Whether connections from the same client are sent to the same real
+	// server.
+	Persistent bool
+	// The persistence timeout in seconds, 0 if not persistent.
+	Timeout uint64
+	// The mask applied to client addresses to group them for persistence,
+	// nil if not persistent.
+	Netmask net.IPMask
+	// The real servers of the service.
+	Backends []IPVSBackend
+}
+
+// IPVSBackend holds current metrics of one real server of a virtual service.
+type IPVSBackend struct {
+	// The real IP address.
+	Address net.IP
+	// The real port.
+	Port uint16
+	// The forwarding method (Masq, Local, Tunnel, Route, Bypass).
+	ForwardMethod string
+	// The current number of active connections to the real server.
+	ActiveConn uint64
+	// The current number of inactive connections to the real server.
+	InactConn uint64
+	// The current weight of the real server.
+	Weight uint64
+}
+
 // NewIPVSStats reads the IPVS statistics.
 func NewIPVSStats() (IPVSStats, error) {
 	fs, err := NewFS(DefaultMountPoint)
@@ -82,8 +171,8 @@ func parseIPVSStats(file io.Reader) (IPVSStats, error) {
 		return IPVSStats{}, err
 	}
 
-	statLines = strings.SplitN(string(statContent), "\n", 4)
-	if len(statLines) != 4 {
+	statLines = strings.Split(string(statContent), "\n")
+	if len(statLines) < 4 {
 		return IPVSStats{}, errors.New("ip_vs_stats corrupt: too short")
 	}
 
@@ -92,30 +181,126 @@ func parseIPVSStats(file io.Reader) (IPVSStats, error) {
 		return IPVSStats{}, errors.New("ip_vs_stats corrupt: unexpected number of fields")
 	}
 
-	stats.Connections, err = strconv.ParseUint(statFields[0], 16, 64)
+	err = parseIPVSCounters(statFields, &stats.Connections, &stats.IncomingPackets,
+		&stats.OutgoingPackets, &stats.IncomingBytes, &stats.OutgoingBytes)
 	if err != nil {
 		return IPVSStats{}, err
 	}
-	stats.IncomingPackets, err = strconv.ParseUint(statFields[1], 16, 64)
-	if err != nil {
-		return IPVSStats{}, err
+
+	// The rates follow after a blank line and another header.
+	if len(statLines) > 5 && len(strings.Fields(statLines[5])) > 0 {
+		statFields = strings.Fields(statLines[5])
+		if len(statFields) != 5 {
+			return IPVSStats{}, errors.New("ip_vs_stats corrupt: unexpected number of rate fields")
+		}
+
+		err = parseIPVSCounters(statFields, &stats.ConnectionRate, &stats.IncomingPacketRate,
+			&stats.OutgoingPacketRate, &stats.IncomingByteRate, &stats.OutgoingByteRate)
+		if err != nil {
+			return IPVSStats{}, err
+		}
 	}
-	stats.OutgoingPackets, err = strconv.ParseUint(statFields[2], 16, 64)
+
+	return stats, nil
+}
+
+// NewIPVSStatsPerCPU reads the IPVS statistics of each CPU.
+func NewIPVSStatsPerCPU() (IPVSStatsPerCPU, error) {
+	fs, err := NewFS(DefaultMountPoint)
 	if err != nil {
-		return IPVSStats{}, err
+		return IPVSStatsPerCPU{}, err
 	}
-	stats.IncomingBytes, err = strconv.ParseUint(statFields[3], 16, 64)
+
+	return fs.NewIPVSStatsPerCPU()
+}
+
+// NewIPVSStatsPerCPU reads the IPVS statistics of each CPU from the specified `proc` filesystem.
+func (fs FS) NewIPVSStatsPerCPU() (IPVSStatsPerCPU, error) {
+	file, err := os.Open(fs.Path("net/ip_vs_stats_percpu"))
 	if err != nil {
-		return IPVSStats{}, err
+		return IPVSStatsPerCPU{}, err
 	}
-	stats.OutgoingBytes, err = strconv.ParseUint(statFields[4], 16, 64)
-	if err != nil {
-		return IPVSStats{}, err
+	defer file.Close()
+
+	return parseIPVSStatsPerCPU(file)
+}
+
+// parseIPVSStatsPerCPU parses `ip_vs_stats_percpu`, which has a line per CPU
+// followed by the totals, marked by "~", and the rates.
+func parseIPVSStatsPerCPU(file io.Reader) (IPVSStatsPerCPU, error) {
+	var (
+		stats   = IPVSStatsPerCPU{CPUs: []IPVSCPUStats{}}
+		scanner = bufio.NewScanner(file)
+		total   bool
+	)
+
+	// Skip the two header lines.
+	for i := 0; i < 2; i++ {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return IPVSStatsPerCPU{}, err
+			}
+			return IPVSStatsPerCPU{}, errors.New("ip_vs_stats_percpu corrupt: too short")
+		}
+	}
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0 || fields[0] == "Conns/s":
+			continue
+		case len(fields) == 5 && total:
+			t := &stats.Total
+			err := parseIPVSCounters(fields, &t.ConnectionRate, &t.IncomingPacketRate,
+				&t.OutgoingPacketRate, &t.IncomingByteRate, &t.OutgoingByteRate)
+			if err != nil {
+				return IPVSStatsPerCPU{}, err
+			}
+		case len(fields) != 6:
+			return IPVSStatsPerCPU{}, fmt.Errorf("ip_vs_stats_percpu corrupt: unexpected number of fields in line %s", scanner.Text())
+		case fields[0] == "~":
+			total = true
+			t := &stats.Total
+			err := parseIPVSCounters(fields[1:], &t.Connections, &t.IncomingPackets,
+				&t.OutgoingPackets, &t.IncomingBytes, &t.OutgoingBytes)
+			if err != nil {
+				return IPVSStatsPerCPU{}, err
+			}
+		default:
+			cpu, err := strconv.ParseUint(fields[0], 16, 32)
+			if err != nil {
+				return IPVSStatsPerCPU{}, err
+			}
+			c := IPVSCPUStats{CPU: int(cpu)}
+			err = parseIPVSCounters(fields[1:], &c.Connections, &c.IncomingPackets,
+				&c.OutgoingPackets, &c.IncomingBytes, &c.OutgoingBytes)
+			if err != nil {
+				return IPVSStatsPerCPU{}, err
+			}
+			stats.CPUs = append(stats.CPUs, c)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return IPVSStatsPerCPU{}, err
+	}
+	if !total {
+		return IPVSStatsPerCPU{}, errors.New("ip_vs_stats_percpu corrupt: missing totals")
 	}
 
 	return stats, nil
 }
 
+// parseIPVSCounters parses hex counters into the values pointed to by vs.
+func parseIPVSCounters(fields []string, vs ...*uint64) error {
+	for i, v := range vs {
+		var err error
+		if *v, err = strconv.ParseUint(fields[i], 16, 64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewIPVSBackendStatus reads and returns the status of all (virtual,real) server pairs.
 func NewIPVSBackendStatus() ([]IPVSBackendStatus, error) {
 	fs, err := NewFS(DefaultMountPoint)
@@ -138,13 +323,57 @@ func (fs FS) NewIPVSBackendStatus() ([]IPVSBackendStatus, error) {
 }
 
 func parseIPVSBackendStatus(file io.Reader) ([]IPVSBackendStatus, error) {
+	services, err := parseIPVSServices(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var status []IPVSBackendStatus
+	for _, svc := range services {
+		for _, b := range svc.Backends {
+			status = append(status, IPVSBackendStatus{
+				LocalAddress:  svc.Address,
+				LocalPort:     svc.Port,
+				RemoteAddress: b.Address,
+				RemotePort:    b.Port,
+				ForwardMethod: b.ForwardMethod,
+				Proto:         svc.Proto,
+				FWMark:        svc.FWMark,
+				Weight:        b.Weight,
+				ActiveConn:    b.ActiveConn,
+				InactConn:     b.InactConn,
+			})
+		}
+	}
+	return status, nil
+}
+
+// NewIPVSServices reads and returns all virtual services with their real
+// servers.
+func NewIPVSServices() ([]IPVSService, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return []IPVSService{}, err
+	}
+
+	return fs.NewIPVSServices()
+}
+
+// NewIPVSServices reads and returns all virtual services with their real servers from the specified `proc` filesystem.
+func (fs FS) NewIPVSServices() ([]IPVSService, error) {
+	file, err := os.Open(fs.Path("net/ip_vs"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseIPVSServices(file)
+}
+
+func parseIPVSServices(file io.Reader) ([]IPVSService, error) {
 	var (
-		status       []IPVSBackendStatus
-		scanner      = bufio.NewScanner(file)
-		proto        string
-		localAddress net.IP
-		localPort    uint16
-		err          error
+		services []IPVSService
+		scanner  = bufio.NewScanner(file)
 	)
 
 	for scanner.Scan() {
@@ -153,22 +382,22 @@ func parseIPVSBackendStatus(file io.Reader) ([]IPVSBackendStatus, error) {
 			continue
 		}
 		switch {
-		case fields[0] == "IP" || fields[0] == "Prot" || fields[1] == "RemoteAddress:Port":
+		case fields[0] == "IP" || fields[0] == "Prot" || len(fields) > 1 && fields[1] == "RemoteAddress:Port":
 			continue
-		case fields[0] == "TCP" || fields[0] == "UDP":
+		case fields[0] == "TCP" || fields[0] == "UDP" || fields[0] == "SCTP" || fields[0] == "FWM":
 			if len(fields) < 2 {
 				continue
 			}
-			proto = fields[0]
-			localAddress, localPort, err = parseIPPort(fields[1])
+			svc, err := parseIPVSService(fields)
 			if err != nil {
 				return nil, err
 			}
+			services = append(services, svc)
 		case fields[0] == "->":
-			if len(fields) < 6 {
+			if len(fields) < 6 || len(services) == 0 {
 				continue
 			}
-			remoteAddress, remotePort, err := parseIPPort(fields[1])
+			address, port, err := parseIPPort(fields[1])
 			if err != nil {
 				return nil, err
 			}
@@ -184,22 +413,95 @@ func parseIPVSBackendStatus(file io.Reader) ([]IPVSBackendStatus, error) {
 			if err != nil {
 				return nil, err
 			}
-			status = append(status, IPVSBackendStatus{
-				LocalAddress:  localAddress,
-				LocalPort:     localPort,
-				RemoteAddress: remoteAddress,
-				RemotePort:    remotePort,
-				Proto:         proto,
+			svc := &services[len(services)-1]
+			svc.Backends = append(svc.Backends, IPVSBackend{
+				Address:       address,
+				Port:          port,
+				ForwardMethod: fields[2],
 				Weight:        weight,
 				ActiveConn:    activeConn,
 				InactConn:     inactConn,
 			})
 		}
 	}
-	return status, nil
+	return services, scanner.Err()
+}
+
+// parseIPVSService parses a service line like
+// "TCP  C0A80016:0CEA wlc persistent 300 FFFFFFFF" or "FWM  00000064 rr".
+// The address family of FWM services isn't printed, so their persistence
+// netmask is always returned as an IPv4 mask.
+func parseIPVSService(fields []string) (IPVSService, error) {
+	var (
+		svc = IPVSService{Proto: fields[0]}
+		err error
+	)
+	if svc.Proto == "FWM" {
+		mark, err := strconv.ParseUint(fields[1], 16, 32)
+		if err != nil {
+			return IPVSService{}, err
+		}
+		svc.FWMark = uint32(mark)
+	} else if svc.Address, svc.Port, err = parseIPPort(fields[1]); err != nil {
+		return IPVSService{}, err
+	}
+	if len(fields) > 2 {
+		svc.Scheduler = fields[2]
+	}
+
+	for i := 3; i < len(fields); i++ {
+		switch fields[i] {
+		case "ops":
+			svc.OnePacket = true
+		case "persistent":
+			if i+2 >= len(fields) {
+				return IPVSService{}, fmt.Errorf("invalid persistence: %s", strings.Join(fields[i:], " "))
+			}
+			svc.Persistent = true
+			if svc.Timeout, err = strconv.ParseUint(fields[i+1], 10, 64); err != nil {
+				return IPVSService{}, err
+			}
+			if svc.Netmask, err = parseIPVSNetmask(fields[i+2], len(svc.Address)); err != nil {
+				return IPVSService{}, err
+			}
+			i += 2
+		}
+	}
+
+	return svc, nil
+}
+
+// parseIPVSNetmask parses the persistence netmask, which is printed as a 32
+// bit hex word. For IPv6 services it holds the prefix length instead of the
+// mask.
+func parseIPVSNetmask(s string, size int) (net.IPMask, error) {
+	if len(s) != 8 {
+		return nil, fmt.Errorf("invalid netmask: %s", s)
+	}
+	mask, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if size == net.IPv6len {
+		ones := binary.BigEndian.Uint32(mask)
+		if ones > 128 {
+			return nil, fmt.Errorf("invalid netmask: %s", s)
+		}
+		return net.CIDRMask(int(ones), 128), nil
+	}
+	return net.IPMask(mask), nil
 }
 
+// parseIPPort parses an address like "C0A80016:0CEA", with the IP address
+// printed in hex in network byte order, or like
+// "[2001:0db8:0000:0000:0000:0000:0000:0001]:0050" as printed for IPv6
+// services by newer kernels.
 func parseIPPort(s string) (net.IP, uint16, error) {
+	if strings.HasPrefix(s, "[") {
+		return parseBracketedIPPort(s)
+	}
+
 	tmp := strings.SplitN(s, ":", 2)
 
 	if len(tmp) != 2 {
@@ -222,3 +524,22 @@ func parseIPPort(s string) (net.IP, uint16, error) {
 
 	return ip, uint16(port), nil
 }
+
+func parseBracketedIPPort(s string) (net.IP, uint16, error) {
+	i := strings.LastIndex(s, "]:")
+	if i < 0 {
+		return nil, 0, fmt.Errorf("invalid IP:Port: %s", s)
+	}
+
+	ip := net.ParseIP(s[1:i])
+	if ip == nil || !strings.Contains(s[1:i], ":") {
+		return nil, 0, fmt.Errorf("invalid IP: %s", s[1:i])
+	}
+
+	port, err := strconv.ParseUint(s[i+2:], 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ip, uint16(port), nil
+}
@@ -0,0 +1,124 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// IPVSConnection holds one entry of the IPVS connection table, as exposed by
+// the kernel in `/proc/net/ip_vs_conn`.
+type IPVSConnection struct {
+	// The transport protocol (TCP, UDP, SCTP).
+	Proto string
+	// The client IP address.
+	ClientAddress net.IP
+	// The client port.
+	ClientPort uint16
+	// The virtual IP address.
+	VirtualAddress net.IP
+	// The virtual port.
+	VirtualPort uint16
+	// The real IP address the connection is forwarded to.
+	DestinationAddress net.IP
+	// The real port the connection is forwarded to.
+	DestinationPort uint16
+	// The connection state, e.g. ESTABLISHED or FIN_WAIT.
+	State string
+	// The seconds until the connection expires.
+	Expires uint64
+	// The persistence engine of the connection, e.g. sip, empty if none.
+	PEName string
+	// The data of the persistence engine, e.g. the SIP Call-ID.
+	PEData string
+}
+
+// IPVSConnections reads and returns all entries of the IPVS connection table.
+func IPVSConnections() ([]IPVSConnection, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return []IPVSConnection{}, err
+	}
+
+	return fs.IPVSConnections()
+}
+
+// IPVSConnections reads and returns all entries of the IPVS connection table from the specified `proc` filesystem.
+func (fs FS) IPVSConnections() ([]IPVSConnection, error) {
+	conns := []IPVSConnection{}
+	err := fs.WalkIPVSConnections(func(c IPVSConnection) error {
+		conns = append(conns, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conns, nil
+}
+
+// WalkIPVSConnections calls fn for each entry of the IPVS connection table,
+// without reading the whole table into memory. It stops at the first error
+// returned by fn.
+func (fs FS) WalkIPVSConnections(fn func(IPVSConnection) error) error {
+	file, err := os.Open(fs.Path("net/ip_vs_conn"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		c, err := parseIPVSConnection(strings.Fields(scanner.Text()))
+		if err != nil {
+			return fmt.Errorf("couldn't parse %s line %s: %s", file.Name(), scanner.Text(), err)
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseIPVSConnection parses the fields of a line like
+// "TCP C0A80001 E1D6 C0A80016 0CEA C0A85216 0CEA ESTABLISHED 898". IPv6
+// addresses are printed in colon-separated form.
+func parseIPVSConnection(fields []string) (IPVSConnection, error) {
+	if len(fields) < 9 {
+		return IPVSConnection{}, fmt.Errorf("expected at least 9 fields, have %d", len(fields))
+	}
+
+	var (
+		c   = IPVSConnection{Proto: fields[0], State: fields[7]}
+		err error
+	)
+	if c.ClientAddress, c.ClientPort, err = parseIPVSConnectionAddr(fields[1], fields[2]); err != nil {
+		return IPVSConnection{}, err
+	}
+	if c.VirtualAddress, c.VirtualPort, err = parseIPVSConnectionAddr(fields[3], fields[4]); err != nil {
+		return IPVSConnection{}, err
+	}
+	if c.DestinationAddress, c.DestinationPort, err = parseIPVSConnectionAddr(fields[5], fields[6]); err != nil {
+		return IPVSConnection{}, err
+	}
+	if c.Expires, err = strconv.ParseUint(fields[8], 10, 64); err != nil {
+		return IPVSConnection{}, err
+	}
+	if len(fields) > 9 {
+		c.PEName = fields[9]
+		c.PEData = strings.Join(fields[10:], " ")
+	}
+
+	return c, nil
+}
+
+func parseIPVSConnectionAddr(ip, port string) (net.IP, uint16, error) {
+	if strings.Contains(ip, ":") {
+		return parseIPPort("[" + ip + "]:" + port)
+	}
+	return parseIPPort(ip + ":" + port)
+}
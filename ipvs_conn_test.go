@@ -0,0 +1,86 @@
+package procfs
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIPVSConnections(t *testing.T) {
+	conns, err := FS("fixtures").IPVSConnections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []IPVSConnection{
+		{
+			Proto:         "TCP",
+			ClientAddress: net.ParseIP("192.168.0.1").To4(), ClientPort: 57814,
+			VirtualAddress: net.ParseIP("192.168.0.22").To4(), VirtualPort: 3306,
+			DestinationAddress: net.ParseIP("192.168.82.22").To4(), DestinationPort: 3306,
+			State: "ESTABLISHED", Expires: 898,
+		},
+		{
+			Proto:         "TCP",
+			ClientAddress: net.ParseIP("192.168.0.2").To4(), ClientPort: 50010,
+			VirtualAddress: net.ParseIP("192.168.0.22").To4(), VirtualPort: 3306,
+			DestinationAddress: net.ParseIP("192.168.83.24").To4(), DestinationPort: 3306,
+			State: "FIN_WAIT", Expires: 97,
+		},
+		{
+			Proto:         "UDP",
+			ClientAddress: net.ParseIP("192.168.0.3").To4(), ClientPort: 36667,
+			VirtualAddress: net.ParseIP("192.168.0.22").To4(), VirtualPort: 5060,
+			DestinationAddress: net.ParseIP("192.168.82.22").To4(), DestinationPort: 5060,
+			State: "UDP", Expires: 298, PEName: "sip", PEData: "a84b4c76e66710@pc33.example.com",
+		},
+		{
+			Proto:         "TCP",
+			ClientAddress: net.ParseIP("2001:db8::a1"), ClientPort: 54321,
+			VirtualAddress: net.ParseIP("2001:db8::1"), VirtualPort: 80,
+			DestinationAddress: net.ParseIP("2001:db8::10"), DestinationPort: 80,
+			State: "TIME_WAIT", Expires: 58,
+		},
+	}
+	if want, have := len(want), len(conns); want != have {
+		t.Fatalf("want %d connections, have %d", want, have)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(want[i], conns[i]) {
+			t.Errorf("%d: want %+v, have %+v", i, want[i], conns[i])
+		}
+	}
+}
+
+func TestWalkIPVSConnectionsStop(t *testing.T) {
+	var (
+		stop = errors.New("stop")
+		n    int
+	)
+	err := FS("fixtures").WalkIPVSConnections(func(IPVSConnection) error {
+		n++
+		return stop
+	})
+	if err != stop {
+		t.Errorf("want error %v, have %v", stop, err)
+	}
+	if want, have := 1, n; want != have {
+		t.Errorf("want %d calls, have %d", want, have)
+	}
+}
+
+func TestParseIPVSConnectionInvalid(t *testing.T) {
+	for _, line := range []string{
+		"TCP C0A80001 E1D6 C0A80016 0CEA C0A85216 0CEA ESTABLISHED",
+		"TCP C0A8000X E1D6 C0A80016 0CEA C0A85216 0CEA ESTABLISHED 898",
+		"TCP C0A80001 E1D6 C0A80016 10000 C0A85216 0CEA ESTABLISHED 898",
+		"TCP C0A80001 E1D6 C0A80016 0CEA 2001:0db8::x 0CEA ESTABLISHED 898",
+		"TCP C0A80001 E1D6 C0A80016 0CEA C0A85216 0CEA ESTABLISHED -1",
+	} {
+		if _, err := parseIPVSConnection(strings.Fields(line)); err == nil {
+			t.Errorf("want error for %q", line)
+		}
+	}
+}
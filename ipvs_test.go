@@ -2,16 +2,23 @@ package procfs
 
 import (
 	"net"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 var (
 	expectedIPVSStats = IPVSStats{
-		Connections:     23765872,
-		IncomingPackets: 3811989221,
-		OutgoingPackets: 0,
-		IncomingBytes:   89991519156915,
-		OutgoingBytes:   0,
+		Connections:        23765872,
+		IncomingPackets:    3811989221,
+		OutgoingPackets:    0,
+		IncomingBytes:      89991519156915,
+		OutgoingBytes:      0,
+		ConnectionRate:     4,
+		IncomingPacketRate: 129852,
+		OutgoingPacketRate: 0,
+		IncomingByteRate:   19409551,
+		OutgoingByteRate:   0,
 	}
 	expectedIPVSBackendStatuses = []IPVSBackendStatus{
 		IPVSBackendStatus{
@@ -19,6 +26,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.82.22"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        100,
 			ActiveConn:    248,
@@ -29,6 +37,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.83.24"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        100,
 			ActiveConn:    248,
@@ -39,6 +48,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.83.21"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        100,
 			ActiveConn:    248,
@@ -49,6 +59,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.84.22"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        0,
 			ActiveConn:    0,
@@ -59,6 +70,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.82.21"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        100,
 			ActiveConn:    1499,
@@ -69,6 +81,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.50.21"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        100,
 			ActiveConn:    1498,
@@ -79,6 +92,7 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.50.26"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        0,
 			ActiveConn:    0,
@@ -89,11 +103,65 @@ var (
 			LocalPort:     3306,
 			RemoteAddress: net.ParseIP("192.168.49.32"),
 			RemotePort:    3306,
+			ForwardMethod: "Tunnel",
 			Proto:         "TCP",
 			Weight:        100,
 			ActiveConn:    0,
 			InactConn:     0,
 		},
+		IPVSBackendStatus{
+			LocalAddress:  net.ParseIP("192.168.0.22"),
+			LocalPort:     53,
+			RemoteAddress: net.ParseIP("192.168.82.22"),
+			RemotePort:    53,
+			ForwardMethod: "Masq",
+			Proto:         "UDP",
+			Weight:        1,
+			ActiveConn:    0,
+			InactConn:     5,
+		},
+		IPVSBackendStatus{
+			Proto:         "FWM",
+			FWMark:        100,
+			RemoteAddress: net.ParseIP("192.168.82.23"),
+			RemotePort:    3306,
+			ForwardMethod: "Route",
+			Weight:        50,
+			ActiveConn:    12,
+			InactConn:     3,
+		},
+		IPVSBackendStatus{
+			Proto:         "FWM",
+			FWMark:        100,
+			RemoteAddress: net.ParseIP("192.168.82.24"),
+			RemotePort:    3306,
+			ForwardMethod: "Route",
+			Weight:        50,
+			ActiveConn:    10,
+			InactConn:     4,
+		},
+		IPVSBackendStatus{
+			LocalAddress:  net.ParseIP("192.168.0.22"),
+			LocalPort:     2905,
+			RemoteAddress: net.ParseIP("192.168.82.22"),
+			RemotePort:    2905,
+			ForwardMethod: "Masq",
+			Proto:         "SCTP",
+			Weight:        1,
+			ActiveConn:    2,
+			InactConn:     0,
+		},
+		IPVSBackendStatus{
+			LocalAddress:  net.ParseIP("2001:db8::1"),
+			LocalPort:     80,
+			RemoteAddress: net.ParseIP("2001:db8::10"),
+			RemotePort:    80,
+			ForwardMethod: "Local",
+			Proto:         "TCP",
+			Weight:        1,
+			ActiveConn:    7,
+			InactConn:     1,
+		},
 	}
 )
 
@@ -108,6 +176,55 @@ func TestIPVSStats(t *testing.T) {
 	}
 }
 
+func TestIPVSStatsWithoutRates(t *testing.T) {
+	stats, err := parseIPVSStats(strings.NewReader(`   Total Incoming Outgoing         Incoming         Outgoing
+   Conns  Packets  Packets            Bytes            Bytes
+ 16AA370 E33656E5        0     51D8C8883AB3                0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := expectedIPVSStats
+	want.ConnectionRate, want.IncomingPacketRate, want.IncomingByteRate = 0, 0, 0
+	if stats != want {
+		t.Errorf("want %+v, have %+v", want, stats)
+	}
+}
+
+func TestIPVSStatsPerCPU(t *testing.T) {
+	stats, err := FS("fixtures").NewIPVSStatsPerCPU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []IPVSCPUStats{
+		{CPU: 0, Connections: 11882864, IncomingPackets: 1906950817, IncomingBytes: 44995759413854},
+		{CPU: 1, Connections: 11883008, IncomingPackets: 1905038404, IncomingBytes: 44995759743061},
+	}
+	if !reflect.DeepEqual(want, stats.CPUs) {
+		t.Errorf("want %+v, have %+v", want, stats.CPUs)
+	}
+	if stats.Total != expectedIPVSStats {
+		t.Errorf("want %+v, have %+v", expectedIPVSStats, stats.Total)
+	}
+}
+
+func TestParseIPVSStatsPerCPUInvalid(t *testing.T) {
+	header := "       Total Incoming Outgoing         Incoming         Outgoing\nCPU    Conns  Packets  Packets            Bytes            Bytes\n"
+	for _, content := range []string{
+		"",
+		header,
+		header + "  0   B55170 71A9C2A1        0     28EC64419A5E\n",
+		header + "  X   B55170 71A9C2A1        0     28EC64419A5E                0\n  ~   B55170 71A9C2A1        0     28EC64419A5E                0\n",
+		header + "  ~   B55170 71A9C2A1        0     28EC64419A5E                G\n",
+	} {
+		if _, err := parseIPVSStatsPerCPU(strings.NewReader(content)); err == nil {
+			t.Errorf("want error for %q", content)
+		}
+	}
+}
+
 func TestParseIPPort(t *testing.T) {
 	ip := net.ParseIP("192.168.0.22")
 	port := uint16(3306)
@@ -128,6 +245,10 @@ func TestParseIPPortInvalid(t *testing.T) {
 		"C0A800:1234",
 		"FOOBARBA:1234",
 		"C0A80016:0CEA:1234",
+		"[2001:0db8:0000:0000:0000:0000:0000:0001:0050",
+		"[2001:0db8:0000:0000:0000:0000:0000:000G]:0050",
+		"[192.168.0.22]:0CEA",
+		"[2001:0db8:0000:0000:0000:0000:0000:0001]:1FFFF",
 	}
 
 	for _, s := range testcases {
@@ -152,6 +273,19 @@ func TestParseIPPortIPv6(t *testing.T) {
 
 }
 
+func TestParseIPPortBracketedIPv6(t *testing.T) {
+	ip := net.ParseIP("2001:db8::1")
+	port := uint16(80)
+
+	gotIP, gotPort, err := parseIPPort("[2001:0db8:0000:0000:0000:0000:0000:0001]:0050")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(gotIP.Equal(ip) && port == gotPort) {
+		t.Errorf("want %s:%d, have %s:%d", ip, port, gotIP, gotPort)
+	}
+}
+
 func TestIPVSBackendStatus(t *testing.T) {
 	backendStats, err := FS("fixtures").NewIPVSBackendStatus()
 	if err != nil {
@@ -174,9 +308,15 @@ func TestIPVSBackendStatus(t *testing.T) {
 		if backendStats[idx].RemotePort != expect.RemotePort {
 			t.Errorf("want RemotePort %d, have %d", expect.RemotePort, backendStats[idx].RemotePort)
 		}
+		if backendStats[idx].ForwardMethod != expect.ForwardMethod {
+			t.Errorf("want ForwardMethod %s, have %s", expect.ForwardMethod, backendStats[idx].ForwardMethod)
+		}
 		if backendStats[idx].Proto != expect.Proto {
 			t.Errorf("want Proto %s, have %s", expect.Proto, backendStats[idx].Proto)
 		}
+		if backendStats[idx].FWMark != expect.FWMark {
+			t.Errorf("want FWMark %d, have %d", expect.FWMark, backendStats[idx].FWMark)
+		}
 		if backendStats[idx].Weight != expect.Weight {
 			t.Errorf("want Weight %d, have %d", expect.Weight, backendStats[idx].Weight)
 		}
@@ -188,3 +328,99 @@ func TestIPVSBackendStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestIPVSServices(t *testing.T) {
+	services, err := FS("fixtures").NewIPVSServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []IPVSService{
+		{Address: net.ParseIP("192.168.0.22"), Port: 3306, Proto: "TCP", Scheduler: "wlc"},
+		{Address: net.ParseIP("192.168.0.57"), Port: 3306, Proto: "TCP", Scheduler: "wlc", Persistent: true, Timeout: 300, Netmask: net.CIDRMask(24, 32)},
+		{Address: net.ParseIP("192.168.0.55"), Port: 3306, Proto: "TCP", Scheduler: "sh"},
+		{Address: net.ParseIP("192.168.0.22"), Port: 53, Proto: "UDP", Scheduler: "rr", OnePacket: true},
+		{Proto: "FWM", FWMark: 100, Scheduler: "wlc", Persistent: true, Timeout: 600, Netmask: net.CIDRMask(32, 32)},
+		{Address: net.ParseIP("192.168.0.22"), Port: 2905, Proto: "SCTP", Scheduler: "rr"},
+		{Address: net.ParseIP("2001:db8::1"), Port: 80, Proto: "TCP", Scheduler: "wlc"},
+	}
+	if want, have := len(want), len(services); want != have {
+		t.Fatalf("want %d services, have %d", want, have)
+	}
+
+	var backends int
+	for i, svc := range services {
+		if !svc.Address.Equal(want[i].Address) || svc.Port != want[i].Port || svc.Proto != want[i].Proto || svc.FWMark != want[i].FWMark {
+			t.Errorf("%d: want %s %s:%d mark %d, have %s %s:%d mark %d", i, want[i].Proto, want[i].Address, want[i].Port, want[i].FWMark, svc.Proto, svc.Address, svc.Port, svc.FWMark)
+		}
+		if svc.Scheduler != want[i].Scheduler {
+			t.Errorf("%d: want scheduler %s, have %s", i, want[i].Scheduler, svc.Scheduler)
+		}
+		if svc.OnePacket != want[i].OnePacket || svc.Persistent != want[i].Persistent || svc.Timeout != want[i].Timeout {
+			t.Errorf("%d: want ops %t, persistent %t, timeout %d, have %t, %t, %d", i, want[i].OnePacket, want[i].Persistent, want[i].Timeout, svc.OnePacket, svc.Persistent, svc.Timeout)
+		}
+		if svc.Netmask.String() != want[i].Netmask.String() {
+			t.Errorf("%d: want netmask %s, have %s", i, want[i].Netmask, svc.Netmask)
+		}
+
+		for _, b := range svc.Backends {
+			expect := expectedIPVSBackendStatuses[backends]
+			if !b.Address.Equal(expect.RemoteAddress) || b.Port != expect.RemotePort || b.ForwardMethod != expect.ForwardMethod {
+				t.Errorf("%d: want backend %s:%d via %s, have %s:%d via %s", i, expect.RemoteAddress, expect.RemotePort, expect.ForwardMethod, b.Address, b.Port, b.ForwardMethod)
+			}
+			if b.Weight != expect.Weight || b.ActiveConn != expect.ActiveConn || b.InactConn != expect.InactConn {
+				t.Errorf("%d: want weight %d, active %d, inactive %d, have %d, %d, %d", i, expect.Weight, expect.ActiveConn, expect.InactConn, b.Weight, b.ActiveConn, b.InactConn)
+			}
+			backends++
+		}
+	}
+	if want, have := len(expectedIPVSBackendStatuses), backends; want != have {
+		t.Errorf("want %d backends, have %d", want, have)
+	}
+}
+
+func TestParseIPVSService(t *testing.T) {
+	for _, tt := range []struct {
+		line    string
+		want    IPVSService
+		invalid bool
+	}{
+		{
+			line: "UDP  C0A80016:0035 rr ops",
+			want: IPVSService{Address: net.ParseIP("192.168.0.22").To4(), Port: 53, Proto: "UDP", Scheduler: "rr", OnePacket: true},
+		},
+		{
+			line: "TCP  C0A80016:01BB sh ops persistent 3600 FFFF0000",
+			want: IPVSService{Address: net.ParseIP("192.168.0.22").To4(), Port: 443, Proto: "TCP", Scheduler: "sh", OnePacket: true, Persistent: true, Timeout: 3600, Netmask: net.CIDRMask(16, 32)},
+		},
+		{
+			line: "TCP  20010DB8000000000000000000000001:0050 wlc   persistent 60 00000040",
+			want: IPVSService{Address: net.ParseIP("2001:db8::1"), Port: 80, Proto: "TCP", Scheduler: "wlc", Persistent: true, Timeout: 60, Netmask: net.CIDRMask(64, 128)},
+		},
+		{
+			line: "FWM  0000000A sh ops",
+			want: IPVSService{Proto: "FWM", FWMark: 10, Scheduler: "sh", OnePacket: true},
+		},
+		{line: "FWM  0000000X sh", invalid: true},
+		{line: "TCP  C0A80016:01BB wlc persistent 300", invalid: true},
+		{line: "TCP  C0A80016:01BB wlc persistent x FFFFFFFF", invalid: true},
+		{line: "TCP  C0A80016:01BB wlc persistent 300 FFFFFF", invalid: true},
+		{line: "TCP  20010DB8000000000000000000000001:0050 wlc persistent 60 00000081", invalid: true},
+		{line: "TCP  C0A80016 wlc", invalid: true},
+	} {
+		have, err := parseIPVSService(strings.Fields(tt.line))
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("want error for %q", tt.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("want %+v, have %+v", tt.want, have)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package procfs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IPVSSource provides samples of the IPVS backend status. FS implements it.
+type IPVSSource interface {
+	NewIPVSBackendStatus() ([]IPVSBackendStatus, error)
+}
+
+// IPVSClock provides the current time and timers to an IPVSWatcher, so that
+// code waiting on time can be tested without sleeping.
+type IPVSClock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// IPVSEventType is the kind of change reported by an IPVSWatcher.
+type IPVSEventType int
+
+// Changes reported by an IPVSWatcher.
+const (
+	// A backend appeared.
+	IPVSBackendAdded IPVSEventType = iota
+	// A backend disappeared.
+	IPVSBackendRemoved
+	// The weight of a backend changed.
+	IPVSWeightChanged
+	// The active connections of a backend reached the threshold.
+	IPVSActiveConnAbove
+	// The active connections of a backend fell below the threshold.
+	IPVSActiveConnBelow
+	// The inactive connections of a backend reached the threshold.
+	IPVSInactConnAbove
+	// The inactive connections of a backend fell below the threshold.
+	IPVSInactConnBelow
+)
+
+var ipvsEventTypeNames = map[IPVSEventType]string{
+	IPVSBackendAdded:    "added",
+	IPVSBackendRemoved:  "removed",
+	IPVSWeightChanged:   "weight_changed",
+	IPVSActiveConnAbove: "active_conn_above",
+	IPVSActiveConnBelow: "active_conn_below",
+	IPVSInactConnAbove:  "inact_conn_above",
+	IPVSInactConnBelow:  "inact_conn_below",
+}
+
+// String returns the name of the event type, e.g. "weight_changed".
+func (t IPVSEventType) String() string {
+	if name, ok := ipvsEventTypeNames[t]; ok {
+		return name
+	}
+	return "IPVSEventType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// IPVSEvent is a change of a backend between two samples.
+type IPVSEvent struct {
+	// The kind of change.
+	Type IPVSEventType
+	// The time of the sample the change was detected in.
+	Time time.Time
+	// The backend as of the sample, or as last seen if it was removed.
+	Backend IPVSBackendStatus
+	// The backend as of the previous sample, zero if it was added.
+	Previous IPVSBackendStatus
+}
+
+// IPVSWatcher periodically samples the IPVS backend status and reports
+// changes between samples.
+type IPVSWatcher struct {
+	// The source of the samples.
+	Source IPVSSource
+	// The clock used for event times and to wait between samples.
+	Clock IPVSClock
+	// The time between samples.
+	Interval time.Duration
+	// The number of active connections to report crossings of, 0 to disable.
+	ActiveConnThreshold uint64
+	// The number of inactive connections to report crossings of, 0 to
+	// disable.
+	InactConnThreshold uint64
+
+	last []IPVSBackendStatus
+}
+
+// NewIPVSWatcher returns a watcher sampling source every interval, using the
+// system clock.
+func NewIPVSWatcher(source IPVSSource, interval time.Duration) *IPVSWatcher {
+	return &IPVSWatcher{Source: source, Clock: realClock{}, Interval: interval}
+}
+
+// Poll takes a sample and returns the changes since the previous one. The
+// first sample is compared against an empty table, so all of its backends
+// are reported as added.
+func (w *IPVSWatcher) Poll() ([]IPVSEvent, error) {
+	status, err := w.Source.NewIPVSBackendStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		now    = w.Clock.Now()
+		events = []IPVSEvent{}
+		last   = make(map[string]IPVSBackendStatus, len(w.last))
+		seen   = make(map[string]bool, len(status))
+	)
+	for _, b := range w.last {
+		last[ipvsBackendKey(b)] = b
+	}
+
+	for _, b := range status {
+		key := ipvsBackendKey(b)
+		seen[key] = true
+
+		prev, ok := last[key]
+		if !ok {
+			events = append(events, IPVSEvent{Type: IPVSBackendAdded, Time: now, Backend: b})
+			continue
+		}
+		if b.Weight != prev.Weight {
+			events = append(events, IPVSEvent{Type: IPVSWeightChanged, Time: now, Backend: b, Previous: prev})
+		}
+		if t, ok := ipvsThresholdEvent(prev.ActiveConn, b.ActiveConn, w.ActiveConnThreshold, IPVSActiveConnAbove, IPVSActiveConnBelow); ok {
+			events = append(events, IPVSEvent{Type: t, Time: now, Backend: b, Previous: prev})
+		}
+		if t, ok := ipvsThresholdEvent(prev.InactConn, b.InactConn, w.InactConnThreshold, IPVSInactConnAbove, IPVSInactConnBelow); ok {
+			events = append(events, IPVSEvent{Type: t, Time: now, Backend: b, Previous: prev})
+		}
+	}
+	for _, b := range w.last {
+		if !seen[ipvsBackendKey(b)] {
+			events = append(events, IPVSEvent{Type: IPVSBackendRemoved, Time: now, Backend: b, Previous: b})
+		}
+	}
+
+	w.last = status
+	return events, nil
+}
+
+// Watch samples every Interval and calls fn for each change until stop is
+// closed or a sample fails.
+func (w *IPVSWatcher) Watch(stop <-chan struct{}, fn func(IPVSEvent)) error {
+	for {
+		events, err := w.Poll()
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			fn(e)
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-w.Clock.After(w.Interval):
+		}
+	}
+}
+
+// ipvsBackendKey identifies a virtual/real server pair across samples.
+func ipvsBackendKey(b IPVSBackendStatus) string {
+	return fmt.Sprintf("%s %s %d %d %s %d", b.Proto, b.LocalAddress, b.LocalPort, b.FWMark, b.RemoteAddress, b.RemotePort)
+}
+
+// ipvsThresholdEvent returns the event for a change from prev to cur if it
+// crosses threshold.
+func ipvsThresholdEvent(prev, cur, threshold uint64, above, below IPVSEventType) (IPVSEventType, bool) {
+	switch {
+	case threshold == 0:
+		return 0, false
+	case prev < threshold && cur >= threshold:
+		return above, true
+	case prev >= threshold && cur < threshold:
+		return below, true
+	}
+	return 0, false
+}
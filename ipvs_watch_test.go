@@ -0,0 +1,160 @@
+package procfs
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now   time.Time
+	ticks chan time.Time
+}
+
+func (c *testClock) Now() time.Time                       { return c.now }
+func (c *testClock) After(time.Duration) <-chan time.Time { return c.ticks }
+
+// testIPVSSource returns the samples of the given file systems in order.
+type testIPVSSource struct {
+	steps []FS
+	n     int
+}
+
+func (s *testIPVSSource) NewIPVSBackendStatus() ([]IPVSBackendStatus, error) {
+	if s.n == len(s.steps) {
+		return nil, errors.New("no more samples")
+	}
+	s.n++
+	return s.steps[s.n-1].NewIPVSBackendStatus()
+}
+
+var ipvsWatchSteps = []FS{
+	"fixtures/ipvs_watch/step1",
+	"fixtures/ipvs_watch/step2",
+	"fixtures/ipvs_watch/step3",
+}
+
+type ipvsWatchEvent struct {
+	typ    IPVSEventType
+	remote string
+}
+
+var ipvsWatchEvents = [][]ipvsWatchEvent{
+	{
+		{IPVSBackendAdded, "192.168.82.22"},
+		{IPVSBackendAdded, "192.168.83.24"},
+	},
+	{
+		{IPVSWeightChanged, "192.168.82.22"},
+		{IPVSActiveConnAbove, "192.168.82.22"},
+		{IPVSBackendAdded, "192.168.83.21"},
+		{IPVSBackendRemoved, "192.168.83.24"},
+	},
+	{
+		{IPVSActiveConnBelow, "192.168.82.22"},
+		{IPVSInactConnAbove, "192.168.82.22"},
+	},
+}
+
+func TestIPVSWatcherPoll(t *testing.T) {
+	var (
+		clock = &testClock{now: time.Unix(1500000000, 0)}
+		w     = NewIPVSWatcher(&testIPVSSource{steps: ipvsWatchSteps}, time.Second)
+	)
+	w.Clock = clock
+	w.ActiveConnThreshold = 100
+	w.InactConnThreshold = 30
+
+	for i, want := range ipvsWatchEvents {
+		clock.now = clock.now.Add(time.Second)
+		events, err := w.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := len(want), len(events); want != have {
+			t.Fatalf("step %d: want %d events, have %d: %+v", i, want, have, events)
+		}
+		for j, e := range events {
+			if e.Type != want[j].typ || !e.Backend.RemoteAddress.Equal(net.ParseIP(want[j].remote)) {
+				t.Errorf("step %d: want %s %s, have %s %s", i, want[j].typ, want[j].remote, e.Type, e.Backend.RemoteAddress)
+			}
+			if !e.Time.Equal(clock.now) {
+				t.Errorf("step %d: want time %s, have %s", i, clock.now, e.Time)
+			}
+		}
+	}
+
+	if _, err := w.Poll(); err == nil {
+		t.Error("want error after the last sample")
+	}
+}
+
+func TestIPVSWatcherPollPrevious(t *testing.T) {
+	w := NewIPVSWatcher(&testIPVSSource{steps: ipvsWatchSteps}, time.Second)
+	if _, err := w.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	events, err := w.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := events[0]
+	if want, have := IPVSWeightChanged, e.Type; want != have {
+		t.Fatalf("want event %s, have %s", want, have)
+	}
+	if want, have := uint64(100), e.Previous.Weight; want != have {
+		t.Errorf("want previous weight %d, have %d", want, have)
+	}
+	if want, have := uint64(50), e.Backend.Weight; want != have {
+		t.Errorf("want weight %d, have %d", want, have)
+	}
+}
+
+func TestIPVSWatcherWatch(t *testing.T) {
+	var (
+		clock  = &testClock{ticks: make(chan time.Time, len(ipvsWatchSteps))}
+		w      = NewIPVSWatcher(&testIPVSSource{steps: ipvsWatchSteps}, time.Second)
+		events []IPVSEvent
+	)
+	w.Clock = clock
+	w.ActiveConnThreshold = 100
+	w.InactConnThreshold = 30
+
+	// After the three samples the fourth one fails, ending the watch.
+	clock.ticks <- time.Time{}
+	clock.ticks <- time.Time{}
+	clock.ticks <- time.Time{}
+	err := w.Watch(make(chan struct{}), func(e IPVSEvent) {
+		events = append(events, e)
+	})
+	if err == nil {
+		t.Fatal("want error after the last sample")
+	}
+
+	var want int
+	for _, step := range ipvsWatchEvents {
+		want += len(step)
+	}
+	if have := len(events); want != have {
+		t.Errorf("want %d events, have %d", want, have)
+	}
+}
+
+func TestIPVSWatcherWatchStop(t *testing.T) {
+	var (
+		w    = NewIPVSWatcher(FS("fixtures"), time.Hour)
+		stop = make(chan struct{})
+		n    int
+	)
+	w.Clock = &testClock{}
+	close(stop)
+
+	if err := w.Watch(stop, func(IPVSEvent) { n++ }); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := len(expectedIPVSBackendStatuses), n; want != have {
+		t.Errorf("want %d events, have %d", want, have)
+	}
+}
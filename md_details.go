@@ -0,0 +1,177 @@
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultSysfsMountPoint is the common mount point of the sys filesystem.
+const DefaultSysfsMountPoint = "/sys"
+
+// MDDetails holds the state of an md array which /proc/mdstat doesn't show,
+// read from /sys/block/<name>/md.
+type MDDetails struct {
+	// The state of the array, e.g. clean, active, readonly or inactive.
+	ArrayState string
+	// Number of sectors found to mismatch by the last check or repair. 0 for
+	// levels without redundancy.
+	MismatchCount int64
+	// The sync action in progress, e.g. idle, resync, recover, check,
+	// repair, reshape or frozen. Empty for levels without redundancy.
+	SyncAction string
+	// Number of sectors done by the sync action in progress, 0 if none.
+	SyncCompleted int64
+	// Number of sectors to be done by the sync action in progress, 0 if none.
+	SyncTotal int64
+	// Number of devices missing from the array.
+	Degraded int64
+	// The member devices in the order of MDStat.Devices.
+	Devices []MDDeviceDetails
+}
+
+// MDDeviceDetails holds the state of a member device of an md array, read
+// from /sys/block/<name>/md/dev-<device>.
+type MDDeviceDetails struct {
+	// Name of the device, e.g. sda1.
+	Name string
+	// The state flags, e.g. in_sync, faulty, spare or write_error.
+	State []string
+	// The slot of the device in the array, -1 if it has none, e.g. as a
+	// spare.
+	Slot int64
+	// Number of read errors corrected on the device.
+	Errors int64
+}
+
+// ReadDetails reads the details of the array from the sys filesystem mounted
+// at sysfs. Attributes which the level of the array doesn't provide and
+// devices which vanished are left out.
+func (md MDStat) ReadDetails(sysfs string) (MDDetails, error) {
+	dir := filepath.Join(sysfs, "block", md.Name, "md")
+	if _, err := os.Stat(dir); err != nil {
+		return MDDetails{}, err
+	}
+
+	var (
+		d   = MDDetails{Devices: []MDDeviceDetails{}}
+		err error
+	)
+	if d.ArrayState, err = readMDAttr(dir, "array_state"); err != nil {
+		return MDDetails{}, err
+	}
+	if d.SyncAction, err = readMDAttr(dir, "sync_action"); err != nil {
+		return MDDetails{}, err
+	}
+	for _, a := range []struct {
+		v    *int64
+		name string
+	}{
+		{&d.MismatchCount, "mismatch_cnt"},
+		{&d.Degraded, "degraded"},
+	} {
+		if *a.v, err = readMDIntAttr(dir, a.name); err != nil {
+			return MDDetails{}, err
+		}
+	}
+
+	completed, err := readMDAttr(dir, "sync_completed")
+	if err != nil {
+		return MDDetails{}, err
+	}
+	if d.SyncCompleted, d.SyncTotal, err = parseMDSyncCompleted(completed); err != nil {
+		return MDDetails{}, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, "sync_completed"), err)
+	}
+
+	for _, dev := range md.Devices {
+		devDir := filepath.Join(dir, "dev-"+dev.Name)
+		if _, err := os.Stat(devDir); os.IsNotExist(err) {
+			continue
+		}
+
+		dd, err := readMDDeviceDetails(devDir, dev.Name)
+		if err != nil {
+			return MDDetails{}, err
+		}
+		d.Devices = append(d.Devices, dd)
+	}
+
+	return d, nil
+}
+
+func readMDDeviceDetails(dir, name string) (MDDeviceDetails, error) {
+	dd := MDDeviceDetails{Name: name, State: []string{}, Slot: -1}
+
+	state, err := readMDAttr(dir, "state")
+	if err != nil {
+		return MDDeviceDetails{}, err
+	}
+	if state != "" {
+		dd.State = strings.Split(state, ",")
+	}
+
+	slot, err := readMDAttr(dir, "slot")
+	if err != nil {
+		return MDDeviceDetails{}, err
+	}
+	if slot != "" && slot != "none" {
+		if dd.Slot, err = strconv.ParseInt(slot, 10, 64); err != nil {
+			return MDDeviceDetails{}, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, "slot"), err)
+		}
+	}
+
+	if dd.Errors, err = readMDIntAttr(dir, "errors"); err != nil {
+		return MDDeviceDetails{}, err
+	}
+
+	return dd, nil
+}
+
+// readMDAttr returns the trimmed content of a sysfs attribute, or an empty
+// string if it doesn't exist.
+func readMDAttr(dir, name string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readMDIntAttr(dir, name string) (int64, error) {
+	s, err := readMDAttr(dir, name)
+	if err != nil || s == "" {
+		return 0, err
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, name), err)
+	}
+	return v, nil
+}
+
+// parseMDSyncCompleted parses sync_completed, which is "none", "delayed" or
+// the sectors done and to be done, like "33551104 / 390620288".
+func parseMDSyncCompleted(s string) (completed, total int64, err error) {
+	if s == "" || s == "none" || s == "delayed" {
+		return 0, 0, nil
+	}
+
+	parts := strings.Split(s, " / ")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected value: %s", s)
+	}
+	if completed, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if total, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return completed, total, nil
+}
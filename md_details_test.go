@@ -0,0 +1,95 @@
+package procfs
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMDStatReadDetails(t *testing.T) {
+	mdStates, err := FS("fixtures").ParseMDStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := map[string]MDDetails{
+		"md0": MDDetails{
+			ArrayState: "clean", MismatchCount: 128, SyncAction: "idle",
+			Devices: []MDDeviceDetails{
+				{Name: "sdk", State: []string{"spare"}, Slot: -1},
+				{Name: "sdi1", State: []string{"in_sync"}, Slot: 0},
+				{Name: "sdj1", State: []string{"in_sync", "write_error"}, Slot: 1, Errors: 12},
+			},
+		},
+		"md6": MDDetails{
+			ArrayState: "active", SyncAction: "recover", SyncCompleted: 33551104, SyncTotal: 390620288, Degraded: 1,
+			Devices: []MDDeviceDetails{
+				{Name: "sdb2", State: []string{}, Slot: 1},
+				{Name: "sda2", State: []string{"in_sync"}, Slot: 0},
+			},
+		},
+		"md11": MDDetails{
+			ArrayState: "clean",
+			Devices: []MDDeviceDetails{
+				{Name: "sdg1", State: []string{"in_sync"}, Slot: 1},
+				{Name: "sdf1", State: []string{"in_sync"}, Slot: 0},
+			},
+		},
+	}
+
+	var n int
+	for _, md := range mdStates {
+		want, ok := refs[md.Name]
+		if !ok {
+			continue
+		}
+		n++
+		have, err := md.ReadDetails("fixtures/sysfs")
+		if err != nil {
+			t.Fatalf("%s: %s", md.Name, err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: want %+v, have %+v", md.Name, want, have)
+		}
+	}
+	if want, have := len(refs), n; want != have {
+		t.Errorf("want %d arrays with details, have %d", want, have)
+	}
+}
+
+func TestMDStatReadDetailsMissing(t *testing.T) {
+	md := MDStat{Name: "md99"}
+	if _, err := md.ReadDetails("fixtures/sysfs"); !os.IsNotExist(err) {
+		t.Errorf("want not exist error, have %v", err)
+	}
+}
+
+func TestParseMDSyncCompleted(t *testing.T) {
+	for _, tt := range []struct {
+		s                string
+		completed, total int64
+		invalid          bool
+	}{
+		{s: "none"},
+		{s: "delayed"},
+		{s: "33551104 / 390620288", completed: 33551104, total: 390620288},
+		{s: "33551104/390620288", invalid: true},
+		{s: "x / 390620288", invalid: true},
+		{s: "33551104 / x", invalid: true},
+	} {
+		completed, total, err := parseMDSyncCompleted(tt.s)
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("want error for %q", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", tt.s, err)
+			continue
+		}
+		if completed != tt.completed || total != tt.total {
+			t.Errorf("want %d / %d, have %d / %d", tt.completed, tt.total, completed, total)
+		}
+	}
+}
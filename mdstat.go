@@ -6,11 +6,19 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
-	statuslineRE = regexp.MustCompile(`(\d+) blocks .*\[(\d+)/(\d+)\] \[[U_]+\]`)
-	buildlineRE  = regexp.MustCompile(`\((\d+)/\d+\)`)
+	statuslineRE = regexp.MustCompile(`^\s*(\d+) blocks`)
+	disksRE      = regexp.MustCompile(`\[(\d+)/(\d+)\] \[[U_]+\]`)
+	superRE      = regexp.MustCompile(` super (\S+)`)
+	levelRE      = regexp.MustCompile(` level (\d+), \d+k chunk, algorithm (\d+)`)
+	chunkRE      = regexp.MustCompile(` (\d+)[kK] (?:chunks?|rounding)`)
+	bitmaplineRE = regexp.MustCompile(`bitmap: (\d+)/(\d+) pages \[(\d+)KB\], (\d+)(KB|B) chunk(?:, file: (.+))?`)
+	buildlineRE  = regexp.MustCompile(`(resync|recovery|reshape|check|repair) *= *([\d.]+)% \((\d+)/\d+\) finish=([\d.]+)min speed=(\d+)K/sec`)
+	syncStateRE  = regexp.MustCompile(`(resync|recovery|reshape|check|repair)=(DELAYED|PENDING)`)
+	deviceRE     = regexp.MustCompile(`^(.+)\[(\d+)\]((?:\([A-Z]\))*)$`)
 )
 
 // MDStat holds info parsed from /proc/mdstat.
@@ -19,14 +27,76 @@ type MDStat struct {
 	Name string
 	// activity-state of the device.
 	ActivityState string
-	// Number of active disks.
+	// Number of active disks. For arrays without redundancy, like raid0 and
+	// linear, and for inactive arrays the kernel doesn't print it, so it is
+	// the number of devices which are neither faulty, spare nor journal.
 	DisksActive int64
-	// Total number of disks the device consists of.
+	// Total number of disks the device consists of. If the kernel doesn't
+	// print it, it is the number of devices listed.
 	DisksTotal int64
 	// Number of blocks the device holds.
 	BlocksTotal int64
-	// Number of blocks on the device that are in sync.
+	// Number of blocks on the device that are in sync, 0 if a sync operation
+	// is delayed or pending.
 	BlocksSynced int64
+	// The raid level, e.g. raid1 or linear.
+	Personality string
+	// The member devices in the order listed.
+	Devices []MDStatDevice
+	// The superblock version, e.g. 1.2, "external:imsm" for containers with
+	// external metadata or "non-persistent". Empty for the default 0.90.
+	Superblock string
+	// The raid level as printed by raid4, raid5 and raid6, 0 otherwise.
+	Level int64
+	// The chunk size in KiB, for linear arrays the rounding. 0 if the level
+	// doesn't use chunks.
+	ChunkSize int64
+	// The parity layout as printed by raid4, raid5 and raid6.
+	Algorithm int64
+	// Whether the array has a write-intent bitmap.
+	Bitmap bool
+	// Number of bitmap pages held in memory.
+	BitmapPages int64
+	// Total number of bitmap pages.
+	BitmapPagesTotal int64
+	// Memory used by the bitmap in KiB.
+	BitmapMemory int64
+	// Size of the chunks tracked by each bitmap bit in bytes.
+	BitmapChunkSize int64
+	// The file holding the bitmap, empty if it is stored with the superblock.
+	BitmapFile string
+	// The sync operation in progress, one of resync, recovery, reshape,
+	// check or repair. Empty if none.
+	SyncOperation string
+	// Whether the sync operation waits for another array sharing a device.
+	SyncDelayed bool
+	// Whether the sync operation waits to be started, e.g. as the array is
+	// read-only.
+	SyncPending bool
+	// Progress of the sync operation in percent.
+	SyncPercent float64
+	// Estimated time until the sync operation finishes.
+	SyncFinish time.Duration
+	// Speed of the sync operation in KiB per second.
+	SyncSpeed int64
+}
+
+// MDStatDevice is a member device of an md array.
+type MDStatDevice struct {
+	// Name of the device, e.g. sda1.
+	Name string
+	// Index of the device in the array, as shown in brackets.
+	Role int64
+	// Whether the device failed (F).
+	Faulty bool
+	// Whether the device is a spare (S).
+	Spare bool
+	// Whether reads are avoided on the device (W).
+	WriteMostly bool
+	// Whether the device is the write journal of the array (J).
+	Journal bool
+	// Whether the device is a replacement for another one (R).
+	Replacement bool
 }
 
 // ParseMDStat parses an mdstat-file and returns a struct with the relevant infos.
@@ -56,83 +126,231 @@ func (fs FS) ParseMDStat() (mdstates []MDStat, err error) {
 		}
 		mdName := mainLine[0]
 		activityState := mainLine[2]
-
-		if len(lines) <= i+3 {
-			return mdStates, fmt.Errorf(
-				"error parsing %s: too few lines for md device %s",
-				mdStatusFilePath,
-				mdName,
-			)
-		}
-
-		active, total, size, err := evalStatusline(lines[i+1])
+		personality, devices, err := evalMainline(mainLine[3:])
 		if err != nil {
 			return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
 		}
 
-		// j is the line number of the syncing-line.
-		j := i + 2
-		if strings.Contains(lines[i+2], "bitmap") { // skip bitmap line
-			j = i + 3
+		md := MDStat{
+			Name:          mdName,
+			ActivityState: activityState,
+			Personality:   personality,
+			Devices:       devices,
+		}
+
+		// j is the line number following the status line, which is missing
+		// for arrays without devices.
+		j := i + 1
+		if j < len(lines) && strings.TrimSpace(lines[j]) != "" {
+			if err := evalStatusline(lines[j], &md); err != nil {
+				return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
+			}
+			j++
 		}
 
 		// If device is syncing at the moment, get the number of currently
-		// synced bytes, otherwise that number equals the size of the device.
-		syncedBlocks := size
-		if strings.Contains(lines[j], "recovery") || strings.Contains(lines[j], "resync") {
-			syncedBlocks, err = evalBuildline(lines[j])
+		// synced blocks, otherwise that number equals the size of the device.
+		// The syncing-line precedes the bitmap line, but both are optional.
+		// Lines which are neither, e.g. ones added by newer kernels, are
+		// skipped.
+		md.BlocksSynced = md.BlocksTotal
+		for ; j < len(lines) && strings.TrimSpace(lines[j]) != ""; j++ {
+			switch {
+			case strings.Contains(lines[j], "bitmap"):
+				err = evalBitmapline(lines[j], &md)
+			case buildlineRE.MatchString(lines[j]) || syncStateRE.MatchString(lines[j]):
+				err = evalBuildline(lines[j], &md)
+			default:
+				continue
+			}
 			if err != nil {
 				return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
 			}
 		}
 
-		mdStates = append(mdStates, MDStat{
-			Name:          mdName,
-			ActivityState: activityState,
-			DisksActive:   active,
-			DisksTotal:    total,
-			BlocksTotal:   size,
-			BlocksSynced:  syncedBlocks,
-		})
+		mdStates = append(mdStates, md)
 	}
 
 	return mdStates, nil
 }
 
-func evalStatusline(statusline string) (active, total, size int64, err error) {
-	matches := statuslineRE.FindStringSubmatch(statusline)
+// evalMainline parses the fields following the activity-state, e.g.
+// "raid1 sdk[2](S) sdi1[0] sdj1[1]".
+func evalMainline(fields []string) (personality string, devices []MDStatDevice, err error) {
+	devices = []MDStatDevice{}
+	for _, f := range fields {
+		switch {
+		case f == "":
+			continue
+		case strings.HasPrefix(f, "("):
+			// Read-only markers like "(auto-read-only)".
+			continue
+		case !strings.Contains(f, "["):
+			personality = f
+			continue
+		}
+
+		d, err := evalDevice(f)
+		if err != nil {
+			return "", nil, err
+		}
+		devices = append(devices, d)
+	}
+
+	return personality, devices, nil
+}
+
+func evalDevice(device string) (MDStatDevice, error) {
+	matches := deviceRE.FindStringSubmatch(device)
 	if len(matches) != 4 {
-		return 0, 0, 0, fmt.Errorf("unexpected statusline: %s", statusline)
+		return MDStatDevice{}, fmt.Errorf("unexpected device: %s", device)
 	}
 
-	size, err = strconv.ParseInt(matches[1], 10, 64)
+	role, err := strconv.ParseInt(matches[2], 10, 64)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+		return MDStatDevice{}, fmt.Errorf("unexpected device %s: %s", device, err)
+	}
+
+	d := MDStatDevice{Name: matches[1], Role: role}
+	for _, flag := range strings.SplitAfter(matches[3], ")") {
+		switch flag {
+		case "":
+		case "(F)":
+			d.Faulty = true
+		case "(S)":
+			d.Spare = true
+		case "(W)":
+			d.WriteMostly = true
+		case "(J)":
+			d.Journal = true
+		case "(R)":
+			d.Replacement = true
+		default:
+			return MDStatDevice{}, fmt.Errorf("unexpected device flag %s: %s", flag, device)
+		}
+	}
+
+	return d, nil
+}
+
+// evalStatusline parses a line like
+// "5853468288 blocks super 1.2 level 6, 64k chunk, algorithm 2 [8/8] [UUUUUUUU]"
+// into md. Only the number of blocks is always present.
+func evalStatusline(statusline string, md *MDStat) error {
+	matches := statuslineRE.FindStringSubmatch(statusline)
+	if len(matches) != 2 {
+		return fmt.Errorf("unexpected statusline: %s", statusline)
 	}
 
-	total, err = strconv.ParseInt(matches[2], 10, 64)
+	var err error
+	md.BlocksTotal, err = strconv.ParseInt(matches[1], 10, 64)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+		return fmt.Errorf("unexpected statusline %s: %s", statusline, err)
 	}
 
-	active, err = strconv.ParseInt(matches[3], 10, 64)
+	if matches := superRE.FindStringSubmatch(statusline); len(matches) == 2 {
+		md.Superblock = matches[1]
+	}
+	if matches := levelRE.FindStringSubmatch(statusline); len(matches) == 3 {
+		if md.Level, err = strconv.ParseInt(matches[1], 10, 64); err != nil {
+			return fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+		}
+		if md.Algorithm, err = strconv.ParseInt(matches[2], 10, 64); err != nil {
+			return fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+		}
+	}
+	if matches := chunkRE.FindStringSubmatch(statusline); len(matches) == 2 {
+		if md.ChunkSize, err = strconv.ParseInt(matches[1], 10, 64); err != nil {
+			return fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+		}
+	}
+
+	matches = disksRE.FindStringSubmatch(statusline)
+	if len(matches) != 3 {
+		md.DisksTotal = int64(len(md.Devices))
+		md.DisksActive = 0
+		for _, d := range md.Devices {
+			if !d.Faulty && !d.Spare && !d.Journal {
+				md.DisksActive++
+			}
+		}
+		return nil
+	}
+
+	md.DisksTotal, err = strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+	}
+	md.DisksActive, err = strconv.ParseInt(matches[2], 10, 64)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("unexpected statusline %s: %s", statusline, err)
+		return fmt.Errorf("unexpected statusline %s: %s", statusline, err)
 	}
 
-	return active, total, size, nil
+	return nil
 }
 
-func evalBuildline(buildline string) (syncedBlocks int64, err error) {
+// evalBitmapline parses a line like
+// "bitmap: 0/30 pages [0KB], 65536KB chunk" into md. The kernel prints the
+// chunk size in bytes if it is less than 1KB.
+func evalBitmapline(bitmapline string, md *MDStat) error {
+	matches := bitmaplineRE.FindStringSubmatch(bitmapline)
+	if len(matches) != 7 {
+		return fmt.Errorf("unexpected bitmapline: %s", bitmapline)
+	}
+
+	for i, v := range []*int64{&md.BitmapPages, &md.BitmapPagesTotal, &md.BitmapMemory, &md.BitmapChunkSize} {
+		var err error
+		if *v, err = strconv.ParseInt(matches[i+1], 10, 64); err != nil {
+			return fmt.Errorf("%s in bitmapline: %s", err, bitmapline)
+		}
+	}
+	if matches[5] == "KB" {
+		md.BitmapChunkSize *= 1024
+	}
+	md.Bitmap = true
+	md.BitmapFile = matches[6]
+
+	return nil
+}
+
+// evalBuildline parses a line describing a sync operation, like
+// "[=>....]  recovery =  8.5% (16775552/195310144) finish=17.0min speed=259783K/sec"
+// or "resync=DELAYED", into md.
+func evalBuildline(buildline string, md *MDStat) error {
+	if matches := syncStateRE.FindStringSubmatch(buildline); len(matches) == 3 {
+		md.SyncOperation = matches[1]
+		md.SyncDelayed = matches[2] == "DELAYED"
+		md.SyncPending = matches[2] == "PENDING"
+		md.BlocksSynced = 0
+		return nil
+	}
+
 	matches := buildlineRE.FindStringSubmatch(buildline)
-	if len(matches) != 2 {
-		return 0, fmt.Errorf("unexpected buildline: %s", buildline)
+	if len(matches) != 6 {
+		return fmt.Errorf("unexpected buildline: %s", buildline)
 	}
 
-	syncedBlocks, err = strconv.ParseInt(matches[1], 10, 64)
+	percent, err := strconv.ParseFloat(matches[2], 64)
+	if err != nil {
+		return fmt.Errorf("%s in buildline: %s", err, buildline)
+	}
+	syncedBlocks, err := strconv.ParseInt(matches[3], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s in buildline: %s", err, buildline)
+	}
+	finish, err := strconv.ParseFloat(matches[4], 64)
+	if err != nil {
+		return fmt.Errorf("%s in buildline: %s", err, buildline)
+	}
+	speed, err := strconv.ParseInt(matches[5], 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("%s in buildline: %s", err, buildline)
+		return fmt.Errorf("%s in buildline: %s", err, buildline)
 	}
 
-	return syncedBlocks, nil
+	md.SyncOperation = matches[1]
+	md.SyncPercent = percent
+	md.SyncFinish = time.Duration(finish * float64(time.Minute))
+	md.SyncSpeed = speed
+	md.BlocksSynced = syncedBlocks
+	return nil
 }
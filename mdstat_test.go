@@ -1,7 +1,12 @@
 package procfs
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestMDStat(t *testing.T) {
@@ -11,21 +16,334 @@ func TestMDStat(t *testing.T) {
 	}
 
 	refs := map[string]MDStat{
-		"md3":   MDStat{"md3", "active", 8, 8, 5853468288, 5853468288},
-		"md127": MDStat{"md127", "active", 2, 2, 312319552, 312319552},
-		"md0":   MDStat{"md0", "active", 2, 2, 248896, 248896},
-		"md4":   MDStat{"md4", "inactive", 2, 2, 4883648, 4883648},
-		"md6":   MDStat{"md6", "active", 1, 2, 195310144, 16775552},
-		"md8":   MDStat{"md8", "active", 2, 2, 195310144, 16775552},
-		"md7":   MDStat{"md7", "active", 3, 4, 7813735424, 7813735424},
+		"md3": MDStat{
+			Name: "md3", ActivityState: "active", DisksActive: 8, DisksTotal: 8,
+			BlocksTotal: 5853468288, BlocksSynced: 5853468288,
+			Personality: "raid6",
+			Superblock:  "1.2", Level: 6, ChunkSize: 64, Algorithm: 2,
+			Devices: []MDStatDevice{
+				{Name: "sda1", Role: 8},
+				{Name: "sdh1", Role: 7},
+				{Name: "sdg1", Role: 6},
+				{Name: "sdf1", Role: 5},
+				{Name: "sde1", Role: 11},
+				{Name: "sdd1", Role: 3},
+				{Name: "sdc1", Role: 10},
+				{Name: "sdb1", Role: 9},
+			},
+		},
+		"md127": MDStat{
+			Name: "md127", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 312319552, BlocksSynced: 312319552,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdi2", Role: 0},
+				{Name: "sdj2", Role: 1, WriteMostly: true},
+			},
+		},
+		"md0": MDStat{
+			Name: "md0", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 248896, BlocksSynced: 248896,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdk", Role: 2, Spare: true},
+				{Name: "sdi1", Role: 0},
+				{Name: "sdj1", Role: 1},
+			},
+		},
+		"md4": MDStat{
+			Name: "md4", ActivityState: "inactive", DisksActive: 0, DisksTotal: 2,
+			BlocksTotal: 4883648, BlocksSynced: 4883648,
+			Devices: []MDStatDevice{
+				{Name: "sda3", Role: 0, Spare: true},
+				{Name: "sdb3", Role: 1, Spare: true},
+			},
+			Superblock: "1.2",
+		},
+		"md6": MDStat{
+			Name: "md6", ActivityState: "active", DisksActive: 1, DisksTotal: 2,
+			BlocksTotal: 195310144, BlocksSynced: 16775552,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdb2", Role: 2},
+				{Name: "sda2", Role: 0},
+			},
+			SyncOperation: "recovery", SyncPercent: 8.5, SyncFinish: 17 * time.Minute, SyncSpeed: 259783,
+		},
+		"md8": MDStat{
+			Name: "md8", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 195310144, BlocksSynced: 16775552,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdb1", Role: 1},
+				{Name: "sda1", Role: 0},
+			},
+			SyncOperation: "resync", SyncPercent: 8.5, SyncFinish: 17 * time.Minute, SyncSpeed: 259783,
+		},
+		"md7": MDStat{
+			Name: "md7", ActivityState: "active", DisksActive: 3, DisksTotal: 4,
+			BlocksTotal: 7813735424, BlocksSynced: 7813735424,
+			Personality: "raid6",
+			Superblock:  "1.2", Level: 6, ChunkSize: 512, Algorithm: 2,
+			Bitmap: true, BitmapPages: 0, BitmapPagesTotal: 30, BitmapMemory: 0, BitmapChunkSize: 67108864,
+			Devices: []MDStatDevice{
+				{Name: "sdb1", Role: 0},
+				{Name: "sde1", Role: 3},
+				{Name: "sdd1", Role: 2},
+				{Name: "sdc1", Role: 1, Faulty: true},
+			},
+		},
+		"md9": MDStat{
+			Name: "md9", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 195310144, BlocksSynced: 33984256,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdc2", Role: 1},
+				{Name: "sdd2", Role: 0},
+			},
+			SyncOperation: "check", SyncPercent: 17.4, SyncFinish: 510 * time.Second, SyncSpeed: 316315,
+			Bitmap: true, BitmapPages: 1, BitmapPagesTotal: 2, BitmapMemory: 4, BitmapChunkSize: 67108864,
+		},
+		"md10": MDStat{
+			Name: "md10", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 195310144, BlocksSynced: 0,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdc3", Role: 1},
+				{Name: "sdd3", Role: 0},
+			},
+			SyncOperation: "resync", SyncDelayed: true,
+		},
+		"md11": MDStat{
+			Name: "md11", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 1953258496, BlocksSynced: 1953258496,
+			Personality: "raid0",
+			Devices: []MDStatDevice{
+				{Name: "sdg1", Role: 1},
+				{Name: "sdf1", Role: 0},
+			},
+			Superblock: "1.2", ChunkSize: 512,
+		},
+		"md12": MDStat{
+			Name: "md12", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 1953258496, BlocksSynced: 1953258496,
+			Personality: "linear",
+			Devices: []MDStatDevice{
+				{Name: "sdh1", Role: 1},
+				{Name: "sdg2", Role: 0},
+			},
+			Superblock: "1.2", ChunkSize: 64,
+		},
+		"md13": MDStat{
+			Name: "md13", ActivityState: "active", DisksActive: 4, DisksTotal: 4,
+			BlocksTotal: 2929893888, BlocksSynced: 2929893888,
+			Personality: "raid10",
+			Devices: []MDStatDevice{
+				{Name: "sdl1", Role: 3},
+				{Name: "sdk1", Role: 2},
+				{Name: "sdj1", Role: 1},
+				{Name: "sdi1", Role: 0},
+			},
+			Superblock: "1.2", ChunkSize: 512,
+			Bitmap: true, BitmapPages: 3, BitmapPagesTotal: 22, BitmapMemory: 12, BitmapChunkSize: 67108864,
+			BitmapFile: "/var/lib/md/md13-bitmap",
+		},
+		"md125": MDStat{
+			Name: "md125", ActivityState: "inactive", DisksActive: 0, DisksTotal: 2,
+			BlocksTotal: 5288, BlocksSynced: 5288,
+			Devices: []MDStatDevice{
+				{Name: "sdn", Role: 1, Spare: true},
+				{Name: "sdm", Role: 0, Spare: true},
+			},
+			Superblock: "external:imsm",
+		},
+		"md126": MDStat{
+			Name: "md126", ActivityState: "active", DisksActive: 2, DisksTotal: 2,
+			BlocksTotal: 976759808, BlocksSynced: 976759808,
+			Personality: "raid1",
+			Devices: []MDStatDevice{
+				{Name: "sdn", Role: 1},
+				{Name: "sdm", Role: 0},
+			},
+			Superblock: "external:/md125/0",
+		},
+		"md14": MDStat{
+			Name: "md14", ActivityState: "inactive",
+			Devices: []MDStatDevice{},
+		},
 	}
 
 	if want, have := len(refs), len(mdStates); want != have {
 		t.Errorf("want %d parsed md-devices, have %d", want, have)
 	}
 	for _, md := range mdStates {
-		if want, have := refs[md.Name], md; want != have {
-			t.Errorf("%s: want %v, have %v", md.Name, want, have)
+		if want, have := refs[md.Name], md; !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: want %+v, have %+v", md.Name, want, have)
+		}
+	}
+}
+
+func TestMDStatUnknownLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `Personalities : [raid1]
+md6 : active raid1 sdb2[2] sda2[0]
+      195310144 blocks [2/1] [U_]
+      [=>...................]  recovery =  8.5% (16775552/195310144) finish=17.0min speed=259783K/sec
+      some future line
+      bitmap: 1/2 pages [4KB], 65536KB chunk
+
+unused devices: <none>
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "mdstat"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mdStates, err := FS(dir).ParseMDStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(mdStates); want != have {
+		t.Fatalf("want %d parsed md-devices, have %d", want, have)
+	}
+	md := mdStates[0]
+	if want, have := int64(16775552), md.BlocksSynced; want != have {
+		t.Errorf("want %d synced blocks, have %d", want, have)
+	}
+	if want, have := "recovery", md.SyncOperation; want != have {
+		t.Errorf("want sync operation %s, have %s", want, have)
+	}
+	if !md.Bitmap {
+		t.Error("want bitmap following the unknown line to be parsed")
+	}
+}
+
+func TestEvalMainline(t *testing.T) {
+	personality, devices, err := evalMainline([]string{"(auto-read-only)", "raid5", "sdd1[4](J)", "sdc1[3](R)", "sdb1[1](W)(F)", "nvme0n1p2[0]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "raid5", personality; want != have {
+		t.Errorf("want personality %s, have %s", want, have)
+	}
+	want := []MDStatDevice{
+		{Name: "sdd1", Role: 4, Journal: true},
+		{Name: "sdc1", Role: 3, Replacement: true},
+		{Name: "sdb1", Role: 1, WriteMostly: true, Faulty: true},
+		{Name: "nvme0n1p2", Role: 0},
+	}
+	if !reflect.DeepEqual(want, devices) {
+		t.Errorf("want %+v, have %+v", want, devices)
+	}
+}
+
+func TestEvalDeviceInvalid(t *testing.T) {
+	for _, device := range []string{
+		"sda1[",
+		"sda1[x]",
+		"[0]",
+		"sda1[0](X)",
+		"sda1[0](S",
+	} {
+		if _, err := evalDevice(device); err == nil {
+			t.Errorf("want error for %q", device)
+		}
+	}
+}
+
+func TestEvalBuildline(t *testing.T) {
+	for _, tt := range []struct {
+		line    string
+		want    MDStat
+		invalid bool
+	}{
+		{
+			line: "      [>....................]  reshape =  1.2% (2348032/195310144) finish=110.3min speed=29149K/sec",
+			want: MDStat{BlocksSynced: 2348032, SyncOperation: "reshape", SyncPercent: 1.2, SyncFinish: 6618 * time.Second, SyncSpeed: 29149},
+		},
+		{
+			line: "      [==================>..]  repair = 92.0% (179685376/195310144) finish=0.9min speed=270336K/sec",
+			want: MDStat{BlocksSynced: 179685376, SyncOperation: "repair", SyncPercent: 92, SyncFinish: 54 * time.Second, SyncSpeed: 270336},
+		},
+		{
+			line: "      \tresync=PENDING",
+			want: MDStat{SyncOperation: "resync", SyncPending: true},
+		},
+		{line: "      [=>...................]  recovery =  8.5% (16775552/195310144)", invalid: true},
+		{line: "      unknown", invalid: true},
+	} {
+		md := MDStat{BlocksSynced: 195310144}
+		err := evalBuildline(tt.line, &md)
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("want error for %q", tt.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.want, md) {
+			t.Errorf("want %+v, have %+v", tt.want, md)
+		}
+	}
+}
+
+func TestEvalBitmapline(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		want MDStat
+	}{
+		{
+			line: "      bitmap: 0/30 pages [0KB], 65536KB chunk",
+			want: MDStat{Bitmap: true, BitmapPagesTotal: 30, BitmapChunkSize: 67108864},
+		},
+		{
+			line: "      bitmap: 1/1 pages [4KB], 512B chunk",
+			want: MDStat{Bitmap: true, BitmapPages: 1, BitmapPagesTotal: 1, BitmapMemory: 4, BitmapChunkSize: 512},
+		},
+		{
+			line: "      bitmap: 3/22 pages [12KB], 64KB chunk, file: /var/lib/md/md13-bitmap",
+			want: MDStat{Bitmap: true, BitmapPages: 3, BitmapPagesTotal: 22, BitmapMemory: 12, BitmapChunkSize: 65536, BitmapFile: "/var/lib/md/md13-bitmap"},
+		},
+	} {
+		var md MDStat
+		if err := evalBitmapline(tt.line, &md); err != nil {
+			t.Errorf("unexpected error for %q: %s", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.want, md) {
+			t.Errorf("want %+v, have %+v", tt.want, md)
+		}
+	}
+}
+
+func TestEvalBitmaplineInvalid(t *testing.T) {
+	for _, line := range []string{
+		"      bitmap: 0/30 pages",
+		"      bitmap: 0/30 pages [0KB], 65536MB chunk",
+		"      bitmap: 99999999999999999999/30 pages [0KB], 65536KB chunk",
+	} {
+		if err := evalBitmapline(line, &MDStat{}); err == nil {
+			t.Errorf("want error for %q", line)
+		}
+	}
+}
+
+func TestEvalStatuslineInvalid(t *testing.T) {
+	for _, line := range []string{
+		"      blocks super 1.2 [2/2] [UU]",
+		"      99999999999999999999 blocks [2/2] [UU]",
+		"      4883648 blocks [99999999999999999999/2] [UU]",
+	} {
+		if err := evalStatusline(line, &MDStat{}); err == nil {
+			t.Errorf("want error for %q", line)
 		}
 	}
 }
@@ -0,0 +1,104 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ARPFlags holds the ATF_* flags of an ARP cache entry.
+type ARPFlags uint32
+
+// ARP entry flags as defined in include/uapi/linux/if_arp.h.
+const (
+	// The entry is complete, i.e. the hardware address is known.
+	ARPFlagComplete ARPFlags = 0x02
+	// The entry is permanent.
+	ARPFlagPermanent ARPFlags = 0x04
+	// The entry is published, i.e. answered on behalf of the address.
+	ARPFlagPublish ARPFlags = 0x08
+	// Trailers are requested.
+	ARPFlagUseTrailers ARPFlags = 0x10
+	// The entry has a netmask, for proxy ARP of whole networks.
+	ARPFlagNetmask ARPFlags = 0x20
+	// The entry is not answered.
+	ARPFlagDontPublish ARPFlags = 0x40
+)
+
+// ARPEntry is a single entry of the ARP cache read from /proc/net/arp.
+type ARPEntry struct {
+	// The IP address.
+	IPAddr net.IP
+	// The hardware type, e.g. 1 for ethernet, see ARPHRD_* in
+	// include/uapi/linux/if_arp.h.
+	HWType uint32
+	// The entry flags.
+	Flags ARPFlags
+	// The hardware address, all zeros for incomplete entries. nil if it
+	// isn't an EUI-48 or EUI-64 address, e.g. an AX.25 callsign.
+	HWAddr net.HardwareAddr
+	// The hardware address as printed in /proc/net/arp.
+	RawHWAddr string
+	// The netmask of proxy ARP entries, "*" otherwise.
+	Mask string
+	// The interface the entry belongs to.
+	Device string
+}
+
+// Complete returns whether the hardware address of the entry is known.
+func (e ARPEntry) Complete() bool {
+	return e.Flags&ARPFlagComplete != 0
+}
+
+// NetARP returns the ARP cache read from /proc/net/arp.
+func (fs FS) NetARP() ([]ARPEntry, error) {
+	f, err := os.Open(fs.Path("net/arp"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		entries = []ARPEntry{}
+		s       = bufio.NewScanner(f)
+	)
+	s.Scan() // skip header
+	for s.Scan() {
+		e, err := parseARPEntry(strings.Fields(s.Text()))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, s.Err()
+}
+
+func parseARPEntry(fields []string) (ARPEntry, error) {
+	if len(fields) != 6 {
+		return ARPEntry{}, fmt.Errorf("expected 6 fields, have %d", len(fields))
+	}
+
+	e := ARPEntry{RawHWAddr: fields[3], Mask: fields[4], Device: fields[5]}
+	if e.IPAddr = net.ParseIP(fields[0]); e.IPAddr == nil {
+		return ARPEntry{}, fmt.Errorf("invalid IP: %s", fields[0])
+	}
+	hwType, err := strconv.ParseUint(strings.TrimPrefix(fields[1], "0x"), 16, 32)
+	if err != nil {
+		return ARPEntry{}, err
+	}
+	e.HWType = uint32(hwType)
+	flags, err := strconv.ParseUint(strings.TrimPrefix(fields[2], "0x"), 16, 32)
+	if err != nil {
+		return ARPEntry{}, err
+	}
+	e.Flags = ARPFlags(flags)
+	if hw, err := net.ParseMAC(fields[3]); err == nil {
+		e.HWAddr = hw
+	}
+
+	return e, nil
+}
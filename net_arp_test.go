@@ -0,0 +1,49 @@
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetARP(t *testing.T) {
+	entries, err := FS("fixtures").NetARP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 4, len(entries); want != have {
+		t.Fatalf("want %d entries, have %d", want, have)
+	}
+
+	for i, want := range []struct {
+		ip, hw, device string
+		flags          ARPFlags
+		complete       bool
+	}{
+		{"192.168.1.1", "e4:8d:8c:00:11:22", "eth0", ARPFlagComplete, true},
+		{"192.168.1.20", "00:00:00:00:00:00", "eth0", 0, false},
+		{"172.17.0.2", "02:42:ac:11:00:02", "docker0", ARPFlagComplete | ARPFlagPermanent, true},
+	} {
+		e := entries[i]
+		if !e.IPAddr.Equal(net.ParseIP(want.ip)) {
+			t.Errorf("%d: want ip %s, have %s", i, want.ip, e.IPAddr)
+		}
+		if e.HWAddr.String() != want.hw || e.RawHWAddr != want.hw {
+			t.Errorf("%d: want hw address %s, have %s", i, want.hw, e.HWAddr)
+		}
+		if e.Device != want.device {
+			t.Errorf("%d: want device %s, have %s", i, want.device, e.Device)
+		}
+		if e.Flags != want.flags || e.Complete() != want.complete {
+			t.Errorf("%d: want flags %#x, have %#x", i, want.flags, e.Flags)
+		}
+		if e.HWType != 1 || e.Mask != "*" {
+			t.Errorf("%d: want hw type 1 and mask *, have %d and %s", i, e.HWType, e.Mask)
+		}
+	}
+
+	// Hardware addresses of other link layers are only kept as printed.
+	e := entries[3]
+	if e.HWType != 3 || e.HWAddr != nil || e.RawHWAddr != "GB7ZZ-1" {
+		t.Errorf("want AX.25 entry with raw hw address GB7ZZ-1, have %+v", e)
+	}
+}
@@ -0,0 +1,329 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetConntrackEntry is a single connection tracked by netfilter, read from
+// /proc/net/nf_conntrack.
+type NetConntrackEntry struct {
+	// The network layer protocol name, "ipv4" or "ipv6".
+	L3Proto string
+	// The network layer protocol number, e.g. 2 for AF_INET.
+	L3ProtoNum uint8
+	// The transport layer protocol name, e.g. "tcp" or "icmp".
+	L4Proto string
+	// The transport layer protocol number, e.g. 6 for TCP.
+	L4ProtoNum uint8
+	// The seconds until the entry expires.
+	Timeout uint64
+	// The connection state, e.g. "ESTABLISHED". Only printed for TCP, SCTP
+	// and DCCP.
+	State string
+	// The tuple of the original direction.
+	Original NetConntrackTuple
+	// The tuple of the reply direction.
+	Reply NetConntrackTuple
+	// Whether no reply has been seen yet.
+	Unreplied bool
+	// Whether the connection has seen traffic in both directions and won't be
+	// evicted early when the table is full.
+	Assured bool
+	// The connection mark, 0 if marks aren't compiled in.
+	Mark uint32
+	// The conntrack zone, 0 for the default zone.
+	Zone uint16
+	// The number of references to the entry.
+	Use uint32
+}
+
+// NetConntrackTuple holds the addresses of one direction of a tracked
+// connection, along with its counters if accounting is enabled via the
+// net.netfilter.nf_conntrack_acct sysctl.
+type NetConntrackTuple struct {
+	// The source address.
+	Src net.IP
+	// The destination address.
+	Dst net.IP
+	// The source port of port based protocols.
+	SrcPort uint16
+	// The destination port of port based protocols.
+	DstPort uint16
+	// The message type of ICMP.
+	Type uint8
+	// The message code of ICMP.
+	Code uint8
+	// The identifier of ICMP echo requests and replies.
+	ID uint16
+	// The packets seen in this direction.
+	Packets uint64
+	// The bytes seen in this direction.
+	Bytes uint64
+	// The zone of this direction, if set separately from the entry zone.
+	Zone uint16
+}
+
+// NetConntrackStat holds the per-CPU conntrack statistics read from
+// /proc/net/stat/nf_conntrack.
+type NetConntrackStat struct {
+	// The CPU the statistics belong to. CPUs which aren't possible on the
+	// system are skipped by the kernel, in which case it is the line number.
+	CPU int
+	// The number of entries in the table. The same on every CPU.
+	Entries uint64
+	// Successful lookups of existing entries.
+	Found uint64
+	// Packets which couldn't be tracked.
+	Invalid uint64
+	// Entries inserted into the table.
+	Insert uint64
+	// Entries which couldn't be inserted, e.g. due to races between CPUs.
+	InsertFailed uint64
+	// Packets dropped because no entry could be created.
+	Drop uint64
+	// Entries dropped to make room for new ones when the table was full.
+	EarlyDrop uint64
+	// Lookups restarted due to hash table resizes or concurrent changes.
+	SearchRestart uint64
+
+	// All counters by the name in the header line, e.g. "icmp_error".
+	Stats map[string]uint64
+}
+
+// NetConntrackUsage holds the size of the conntrack table read from the
+// net.netfilter sysctls.
+type NetConntrackUsage struct {
+	// The number of entries, from nf_conntrack_count.
+	Count uint64
+	// The maximum number of entries, from nf_conntrack_max.
+	Max uint64
+}
+
+// WalkNetConntrack calls fn for each entry in /proc/net/nf_conntrack, without
+// reading the whole table into memory. It stops at the first error returned
+// by fn.
+func (fs FS) WalkNetConntrack(fn func(NetConntrackEntry) error) error {
+	f, err := os.Open(fs.Path("net/nf_conntrack"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		e, err := parseNetConntrackEntry(strings.Fields(s.Text()))
+		if err != nil {
+			return fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
+
+// NetConntrack returns all entries read from /proc/net/nf_conntrack.
+func (fs FS) NetConntrack() ([]NetConntrackEntry, error) {
+	entries := []NetConntrackEntry{}
+	err := fs.WalkNetConntrack(func(e NetConntrackEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// NetConntrackStat returns the per-CPU statistics read from
+// /proc/net/stat/nf_conntrack.
+func (fs FS) NetConntrackStat() ([]NetConntrackStat, error) {
+	f, err := os.Open(fs.Path("net/stat/nf_conntrack"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("couldn't parse %s: missing header", f.Name())
+	}
+	names := strings.Fields(s.Text())
+
+	stats := []NetConntrackStat{}
+	for cpu := 0; s.Scan(); cpu++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) != len(names) {
+			return nil, fmt.Errorf("couldn't parse %s line %s: expected %d fields, have %d", f.Name(), s.Text(), len(names), len(fields))
+		}
+
+		st := NetConntrackStat{CPU: cpu, Stats: make(map[string]uint64, len(names))}
+		for i, field := range fields {
+			v, err := strconv.ParseUint(field, 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+			}
+			st.Stats[names[i]] = v
+		}
+		st.Entries = st.Stats["entries"]
+		st.Found = st.Stats["found"]
+		st.Invalid = st.Stats["invalid"]
+		st.Insert = st.Stats["insert"]
+		st.InsertFailed = st.Stats["insert_failed"]
+		st.Drop = st.Stats["drop"]
+		st.EarlyDrop = st.Stats["early_drop"]
+		st.SearchRestart = st.Stats["search_restart"]
+		stats = append(stats, st)
+	}
+
+	return stats, s.Err()
+}
+
+// NetConntrackUsage returns the number of conntrack entries and the table
+// limit read from /proc/sys/net/netfilter.
+func (fs FS) NetConntrackUsage() (NetConntrackUsage, error) {
+	var u NetConntrackUsage
+	for _, c := range []struct {
+		v    *uint64
+		name string
+	}{
+		{&u.Count, "nf_conntrack_count"},
+		{&u.Max, "nf_conntrack_max"},
+	} {
+		file := fs.Path("sys/net/netfilter", c.name)
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return NetConntrackUsage{}, err
+		}
+		if *c.v, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return NetConntrackUsage{}, fmt.Errorf("couldn't parse %s: %s", file, err)
+		}
+	}
+
+	return u, nil
+}
+
+// parseNetConntrackEntry parses the fields of a line like
+//
+//	ipv4 2 tcp 6 431999 ESTABLISHED src=10.0.0.5 dst=10.0.0.1 sport=51234 dport=443 ...
+//
+// The key-value pairs of the original direction are followed by those of the
+// reply direction, which start with the second src key.
+func parseNetConntrackEntry(fields []string) (NetConntrackEntry, error) {
+	if len(fields) < 5 {
+		return NetConntrackEntry{}, fmt.Errorf("expected at least 5 fields, have %d", len(fields))
+	}
+
+	e := NetConntrackEntry{L3Proto: fields[0], L4Proto: fields[2]}
+	l3, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return NetConntrackEntry{}, err
+	}
+	e.L3ProtoNum = uint8(l3)
+	l4, err := strconv.ParseUint(fields[3], 10, 8)
+	if err != nil {
+		return NetConntrackEntry{}, err
+	}
+	e.L4ProtoNum = uint8(l4)
+	if e.Timeout, err = strconv.ParseUint(fields[4], 10, 64); err != nil {
+		return NetConntrackEntry{}, err
+	}
+
+	fields = fields[5:]
+	if len(fields) > 0 && !strings.Contains(fields[0], "=") && !strings.HasPrefix(fields[0], "[") {
+		e.State = fields[0]
+		fields = fields[1:]
+	}
+
+	tuple := &e.Original
+	for _, field := range fields {
+		switch field {
+		case "[UNREPLIED]":
+			e.Unreplied = true
+			continue
+		case "[ASSURED]":
+			e.Assured = true
+			continue
+		}
+
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			// Other flags like [OFFLOAD] and unknown fields are ignored.
+			continue
+		}
+		if kv[0] == "src" && tuple.Src != nil {
+			if tuple == &e.Reply {
+				return NetConntrackEntry{}, fmt.Errorf("unexpected third tuple")
+			}
+			tuple = &e.Reply
+		}
+
+		var v uint64
+		switch kv[0] {
+		case "src", "dst":
+			ip := net.ParseIP(kv[1])
+			if ip == nil {
+				return NetConntrackEntry{}, fmt.Errorf("invalid IP: %s", kv[1])
+			}
+			if kv[0] == "src" {
+				tuple.Src = ip
+			} else {
+				tuple.Dst = ip
+			}
+			continue
+		case "sport", "dport", "id", "zone", "zone-orig", "zone-reply":
+			v, err = strconv.ParseUint(kv[1], 10, 16)
+		case "type", "code":
+			v, err = strconv.ParseUint(kv[1], 10, 8)
+		case "mark", "use":
+			v, err = strconv.ParseUint(kv[1], 10, 32)
+		case "packets", "bytes":
+			v, err = strconv.ParseUint(kv[1], 10, 64)
+		default:
+			continue
+		}
+		if err != nil {
+			return NetConntrackEntry{}, err
+		}
+
+		switch kv[0] {
+		case "sport":
+			tuple.SrcPort = uint16(v)
+		case "dport":
+			tuple.DstPort = uint16(v)
+		case "id":
+			tuple.ID = uint16(v)
+		case "type":
+			tuple.Type = uint8(v)
+		case "code":
+			tuple.Code = uint8(v)
+		case "packets":
+			tuple.Packets = v
+		case "bytes":
+			tuple.Bytes = v
+		case "zone-orig", "zone-reply":
+			tuple.Zone = uint16(v)
+		case "zone":
+			e.Zone = uint16(v)
+		case "mark":
+			e.Mark = uint32(v)
+		case "use":
+			e.Use = uint32(v)
+		}
+	}
+	if e.Original.Src == nil || e.Reply.Src == nil {
+		return NetConntrackEntry{}, fmt.Errorf("missing tuple")
+	}
+
+	return e, nil
+}
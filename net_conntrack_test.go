@@ -0,0 +1,121 @@
+package procfs
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNetConntrack(t *testing.T) {
+	entries, err := FS("fixtures").NetConntrack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []NetConntrackEntry{
+		{
+			L3Proto: "ipv4", L3ProtoNum: 2, L4Proto: "tcp", L4ProtoNum: 6, Timeout: 431999, State: "ESTABLISHED",
+			Original: NetConntrackTuple{Src: net.ParseIP("10.0.0.5"), Dst: net.ParseIP("93.184.216.34"), SrcPort: 51234, DstPort: 443, Packets: 10, Bytes: 1234},
+			Reply:    NetConntrackTuple{Src: net.ParseIP("93.184.216.34"), Dst: net.ParseIP("10.0.0.5"), SrcPort: 443, DstPort: 51234, Packets: 8, Bytes: 5678},
+			Assured:  true, Use: 2,
+		},
+		{
+			L3Proto: "ipv4", L3ProtoNum: 2, L4Proto: "udp", L4ProtoNum: 17, Timeout: 28,
+			Original:  NetConntrackTuple{Src: net.ParseIP("10.0.0.5"), Dst: net.ParseIP("8.8.8.8"), SrcPort: 40000, DstPort: 53, Packets: 1, Bytes: 60},
+			Reply:     NetConntrackTuple{Src: net.ParseIP("8.8.8.8"), Dst: net.ParseIP("10.0.0.5"), SrcPort: 53, DstPort: 40000},
+			Unreplied: true, Mark: 16, Zone: 3, Use: 1,
+		},
+		{
+			L3Proto: "ipv4", L3ProtoNum: 2, L4Proto: "icmp", L4ProtoNum: 1, Timeout: 29,
+			Original: NetConntrackTuple{Src: net.ParseIP("10.0.0.5"), Dst: net.ParseIP("10.0.0.1"), Type: 8, ID: 4321, Packets: 1, Bytes: 84},
+			Reply:    NetConntrackTuple{Src: net.ParseIP("10.0.0.1"), Dst: net.ParseIP("10.0.0.5"), ID: 4321, Packets: 1, Bytes: 84},
+			Use:      1,
+		},
+		{
+			L3Proto: "ipv6", L3ProtoNum: 10, L4Proto: "tcp", L4ProtoNum: 6, Timeout: 117, State: "TIME_WAIT",
+			Original: NetConntrackTuple{Src: net.ParseIP("2001:db8::1"), Dst: net.ParseIP("2001:db8::2"), SrcPort: 34567, DstPort: 80},
+			Reply:    NetConntrackTuple{Src: net.ParseIP("2001:db8::2"), Dst: net.ParseIP("2001:db8::1"), SrcPort: 80, DstPort: 34567},
+			Assured:  true, Use: 2,
+		},
+	}
+	if want, have := len(want), len(entries); want != have {
+		t.Fatalf("want %d entries, have %d", want, have)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(want[i], entries[i]) {
+			t.Errorf("%d: want %+v, have %+v", i, want[i], entries[i])
+		}
+	}
+}
+
+func TestWalkNetConntrackStop(t *testing.T) {
+	var (
+		stop = errors.New("stop")
+		n    int
+	)
+	err := FS("fixtures").WalkNetConntrack(func(NetConntrackEntry) error {
+		n++
+		return stop
+	})
+	if err != stop {
+		t.Errorf("want error %v, have %v", stop, err)
+	}
+	if want, have := 1, n; want != have {
+		t.Errorf("want %d calls, have %d", want, have)
+	}
+}
+
+func TestParseNetConntrackEntryInvalid(t *testing.T) {
+	for _, line := range []string{
+		"ipv4 2 tcp 6",
+		"ipv4 x tcp 6 10 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 src=10.0.0.2 dst=10.0.0.1",
+		"ipv4 2 tcp 6 10 ESTABLISHED src=10.0.0.1 dst=10.0.0.2",
+		"ipv4 2 tcp 6 10 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 sport=70000 src=10.0.0.2 dst=10.0.0.1",
+		"ipv4 2 tcp 6 10 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 src=10.0.0.2 dst=10.0.0.x",
+		"ipv4 2 tcp 6 10 ESTABLISHED src=10.0.0.1 src=10.0.0.2 src=10.0.0.3",
+	} {
+		if _, err := parseNetConntrackEntry(strings.Fields(line)); err == nil {
+			t.Errorf("want error for %q", line)
+		}
+	}
+}
+
+func TestNetConntrackStat(t *testing.T) {
+	stats, err := FS("fixtures").NetConntrackStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 2, len(stats); want != have {
+		t.Fatalf("want %d CPUs, have %d", want, have)
+	}
+
+	for i, want := range []NetConntrackStat{
+		{CPU: 0, Entries: 33, Invalid: 5, SearchRestart: 17},
+		{CPU: 1, Entries: 33, Invalid: 42, InsertFailed: 2, Drop: 7, EarlyDrop: 4, SearchRestart: 200},
+	} {
+		have := stats[i]
+		if want, have := 17, len(have.Stats); want != have {
+			t.Errorf("%d: want %d counters, have %d", i, want, have)
+		}
+		if want, have := []uint64{0x1c4a, 0x2f1b}[i], have.Stats["ignore"]; want != have {
+			t.Errorf("%d: want ignore %d, have %d", i, want, have)
+		}
+		have.Stats = nil
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%d: want %+v, have %+v", i, want, have)
+		}
+	}
+}
+
+func TestNetConntrackUsage(t *testing.T) {
+	u, err := FS("fixtures").NetConntrackUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := (NetConntrackUsage{Count: 33, Max: 262144}), u; want != have {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+}
@@ -0,0 +1,121 @@
+package procfs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetDevLine is a single line parsed from /proc/net/dev or /proc/[pid]/net/dev.
+type NetDevLine struct {
+	// The name of the interface.
+	Name string
+	// Received bytes.
+	RxBytes uint64
+	// Received packets.
+	RxPackets uint64
+	// Receive errors.
+	RxErrors uint64
+	// Dropped received packets.
+	RxDropped uint64
+	// Receive FIFO buffer errors.
+	RxFIFO uint64
+	// Receive packet framing errors.
+	RxFrame uint64
+	// Received compressed packets.
+	RxCompressed uint64
+	// Received multicast frames.
+	RxMulticast uint64
+	// Transmitted bytes.
+	TxBytes uint64
+	// Transmitted packets.
+	TxPackets uint64
+	// Transmit errors.
+	TxErrors uint64
+	// Dropped transmitted packets.
+	TxDropped uint64
+	// Transmit FIFO buffer errors.
+	TxFIFO uint64
+	// Collisions detected on the interface.
+	TxCollisions uint64
+	// Carrier losses detected on the interface.
+	TxCarrier uint64
+	// Transmitted compressed packets.
+	TxCompressed uint64
+}
+
+// NetDev is parsed from /proc/net/dev or /proc/[pid]/net/dev. The map keys
+// are interface names.
+type NetDev map[string]NetDevLine
+
+// NetDev returns the interface statistics of the network namespace of the
+// proc mount, read from /proc/net/dev.
+func (fs FS) NetDev() (NetDev, error) {
+	return newNetDev(fs.Path("net/dev"))
+}
+
+// NetDev returns the interface statistics of the network namespace of the
+// process, read from /proc/[pid]/net/dev.
+func (p Proc) NetDev() (NetDev, error) {
+	return newNetDev(p.path("net/dev"))
+}
+
+func newNetDev(file string) (NetDev, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		nd = NetDev{}
+		s  = bufio.NewScanner(f)
+	)
+	for n := 0; s.Scan(); n++ {
+		// Skip the two header lines.
+		if n < 2 {
+			continue
+		}
+
+		l, err := parseNetDevLine(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		nd[l.Name] = l
+	}
+
+	return nd, s.Err()
+}
+
+func parseNetDevLine(line string) (NetDevLine, error) {
+	// The interface name is followed by a colon, which isn't necessarily
+	// followed by a space if the first counter is large.
+	i := strings.LastIndex(line, ":")
+	if i < 0 {
+		return NetDevLine{}, errors.New("missing interface name")
+	}
+
+	fields := strings.Fields(line[i+1:])
+	if len(fields) != 16 {
+		return NetDevLine{}, fmt.Errorf("expected 16 fields, have %d", len(fields))
+	}
+
+	l := NetDevLine{Name: strings.TrimSpace(line[:i])}
+	for j, v := range []*uint64{
+		&l.RxBytes, &l.RxPackets, &l.RxErrors, &l.RxDropped,
+		&l.RxFIFO, &l.RxFrame, &l.RxCompressed, &l.RxMulticast,
+		&l.TxBytes, &l.TxPackets, &l.TxErrors, &l.TxDropped,
+		&l.TxFIFO, &l.TxCollisions, &l.TxCarrier, &l.TxCompressed,
+	} {
+		u, err := strconv.ParseUint(fields[j], 10, 64)
+		if err != nil {
+			return NetDevLine{}, err
+		}
+		*v = u
+	}
+
+	return l, nil
+}
@@ -0,0 +1,61 @@
+package procfs
+
+import "testing"
+
+func TestNetDev(t *testing.T) {
+	nd, err := FS("fixtures").NetDev()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 5, len(nd); want != have {
+		t.Errorf("want %d interfaces, have %d", want, have)
+	}
+
+	want := NetDevLine{Name: "eth0", RxBytes: 874354587, RxPackets: 1036395, TxBytes: 563352563, TxPackets: 732147}
+	if have := nd["eth0"]; want != have {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+
+	want = NetDevLine{
+		Name:         "wlan0",
+		RxBytes:      7141563581,
+		RxPackets:    5463829,
+		RxErrors:     11,
+		RxDropped:    2134,
+		RxFIFO:       1,
+		RxFrame:      7,
+		RxCompressed: 3,
+		RxMulticast:  1022,
+		TxBytes:      2043564739,
+		TxPackets:    3436612,
+		TxErrors:     4,
+		TxDropped:    12,
+		TxFIFO:       2,
+		TxCollisions: 9,
+		TxCarrier:    13,
+		TxCompressed: 5,
+	}
+	if have := nd["wlan0"]; want != have {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+}
+
+func TestProcNetDev(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd, err := p.NetDev()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NetDevLine{Name: "eth0", RxBytes: 438, RxPackets: 5, TxBytes: 648, TxPackets: 8}
+	if have := nd["eth0"]; want != have {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+	if want, have := 2, len(nd); want != have {
+		t.Errorf("want %d interfaces, have %d", want, have)
+	}
+}
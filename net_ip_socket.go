@@ -0,0 +1,231 @@
+package procfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TCPState is the state of a socket as printed in the st column of the
+// /proc/net socket tables. UDP and raw sockets use TCPEstablished and
+// TCPClose as well.
+type TCPState uint8
+
+// Socket states as defined in include/net/tcp_states.h.
+const (
+	TCPEstablished TCPState = iota + 1
+	TCPSynSent
+	TCPSynRecv
+	TCPFinWait1
+	TCPFinWait2
+	TCPTimeWait
+	TCPClose
+	TCPCloseWait
+	TCPLastAck
+	TCPListen
+	TCPClosing
+	TCPNewSynRecv
+)
+
+var tcpStateNames = map[TCPState]string{
+	TCPEstablished: "ESTABLISHED",
+	TCPSynSent:     "SYN_SENT",
+	TCPSynRecv:     "SYN_RECV",
+	TCPFinWait1:    "FIN_WAIT1",
+	TCPFinWait2:    "FIN_WAIT2",
+	TCPTimeWait:    "TIME_WAIT",
+	TCPClose:       "CLOSE",
+	TCPCloseWait:   "CLOSE_WAIT",
+	TCPLastAck:     "LAST_ACK",
+	TCPListen:      "LISTEN",
+	TCPClosing:     "CLOSING",
+	TCPNewSynRecv:  "NEW_SYN_RECV",
+}
+
+// String returns the name of the state, e.g. "LISTEN".
+func (s TCPState) String() string {
+	if name, ok := tcpStateNames[s]; ok {
+		return name
+	}
+	return "TCPState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// NetIPSocketLine is a single line of one of the IP socket tables like
+// /proc/net/tcp, /proc/net/udp6 or /proc/net/raw.
+type NetIPSocketLine struct {
+	// The slot of the socket in the kernel hash table.
+	Sl uint64
+	// The local IP address.
+	LocalAddr net.IP
+	// The local port.
+	LocalPort uint16
+	// The remote IP address.
+	RemAddr net.IP
+	// The remote port.
+	RemPort uint16
+	// The socket state.
+	State TCPState
+	// The size of the send queue in bytes.
+	TxQueue uint64
+	// The size of the receive queue in bytes.
+	RxQueue uint64
+	// The kind of timer pending on the socket, e.g. 1 for the retransmit
+	// timer, 0 for none.
+	TimerActive uint8
+	// The jiffies until the timer expires.
+	TimerExpires uint64
+	// The number of unrecovered retransmission timeouts.
+	Retransmits uint64
+	// The effective UID of the socket owner.
+	UID uint32
+	// The number of unanswered zero window probes.
+	Timeout uint64
+	// The inode of the socket, see the socket:[inode] links in
+	// /proc/[pid]/fd. 0 for sockets in TIME_WAIT.
+	Inode uint64
+	// The number of dropped datagrams. Only available for UDP, UDP-Lite and
+	// raw sockets.
+	Drops uint64
+}
+
+func collectNetIPSocket(walk func(func(NetIPSocketLine) error) error) ([]NetIPSocketLine, error) {
+	lines := []NetIPSocketLine{}
+	err := walk(func(l NetIPSocketLine) error {
+		lines = append(lines, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// walkNetIPSocket calls fn for each line of the socket table in file. If
+// drops is true, the table is expected to have a drops column as printed for
+// UDP, UDP-Lite and raw sockets.
+func walkNetIPSocket(file string, drops bool, fn func(NetIPSocketLine) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Scan() // skip header
+	for s.Scan() {
+		l, err := parseNetIPSocketLine(strings.Fields(s.Text()), drops)
+		if err != nil {
+			return fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
+
+func parseNetIPSocketLine(fields []string, drops bool) (NetIPSocketLine, error) {
+	if len(fields) < 10 {
+		return NetIPSocketLine{}, fmt.Errorf("expected at least 10 fields, have %d", len(fields))
+	}
+	if drops && len(fields) < 13 {
+		return NetIPSocketLine{}, fmt.Errorf("expected 13 fields, have %d", len(fields))
+	}
+
+	var (
+		l   NetIPSocketLine
+		err error
+	)
+	if l.Sl, err = strconv.ParseUint(strings.TrimSuffix(fields[0], ":"), 10, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	if l.LocalAddr, l.LocalPort, err = parseNetIPSocketAddr(fields[1]); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	if l.RemAddr, l.RemPort, err = parseNetIPSocketAddr(fields[2]); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	state, err := strconv.ParseUint(fields[3], 16, 8)
+	if err != nil {
+		return NetIPSocketLine{}, err
+	}
+	l.State = TCPState(state)
+
+	queues := strings.SplitN(fields[4], ":", 2)
+	if len(queues) != 2 {
+		return NetIPSocketLine{}, fmt.Errorf("invalid queue sizes: %s", fields[4])
+	}
+	if l.TxQueue, err = strconv.ParseUint(queues[0], 16, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	if l.RxQueue, err = strconv.ParseUint(queues[1], 16, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+
+	timer := strings.SplitN(fields[5], ":", 2)
+	if len(timer) != 2 {
+		return NetIPSocketLine{}, fmt.Errorf("invalid timer: %s", fields[5])
+	}
+	active, err := strconv.ParseUint(timer[0], 16, 8)
+	if err != nil {
+		return NetIPSocketLine{}, err
+	}
+	l.TimerActive = uint8(active)
+	if l.TimerExpires, err = strconv.ParseUint(timer[1], 16, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+
+	if l.Retransmits, err = strconv.ParseUint(fields[6], 16, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	uid, err := strconv.ParseUint(fields[7], 10, 32)
+	if err != nil {
+		return NetIPSocketLine{}, err
+	}
+	l.UID = uint32(uid)
+	if l.Timeout, err = strconv.ParseUint(fields[8], 10, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	if l.Inode, err = strconv.ParseUint(fields[9], 10, 64); err != nil {
+		return NetIPSocketLine{}, err
+	}
+	if drops {
+		if l.Drops, err = strconv.ParseUint(fields[12], 10, 64); err != nil {
+			return NetIPSocketLine{}, err
+		}
+	}
+
+	return l, nil
+}
+
+// parseNetIPSocketAddr parses an address like "0100007F:0016". Unlike the
+// IPVS tables, the socket tables print addresses as a sequence of 32 bit
+// words in host byte order and never in brackets.
+func parseNetIPSocketAddr(s string) (net.IP, uint16, error) {
+	if strings.HasPrefix(s, "[") {
+		return nil, 0, fmt.Errorf("invalid IP:Port: %s", s)
+	}
+
+	ip, port, err := parseIPPort(s)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hostOrderIP(ip), port, nil
+}
+
+// hostOrderIP converts an IP address decoded from a sequence of 32 bit words
+// printed in host byte order to network byte order, in place. Each word is
+// printed as a hex number, so the decoded bytes hold the value of a word read
+// from the address in host byte order.
+func hostOrderIP(ip net.IP) net.IP {
+	for i := 0; i+4 <= len(ip); i += 4 {
+		binary.NativeEndian.PutUint32(ip[i:], binary.BigEndian.Uint32(ip[i:]))
+	}
+	return ip
+}
@@ -0,0 +1,136 @@
+package procfs
+
+import (
+	"net"
+	"os"
+)
+
+// ListeningPort is a listening TCP socket or a bound, unconnected UDP socket
+// along with a process holding it open.
+type ListeningPort struct {
+	// The process holding the socket open. Its PID is 0 if no such process is
+	// visible to the caller.
+	Proc Proc
+	// The absolute path of the executable of the process, empty if it can't
+	// be read due to missing permissions.
+	Executable string
+	// The command name of the process.
+	Comm string
+	// The command line of the process.
+	CmdLine []string
+	// The transport protocol, "tcp" or "udp".
+	Protocol string
+	// The address family, "inet" or "inet6".
+	Family string
+	// The address the socket is bound to.
+	Address net.IP
+	// The port the socket is bound to.
+	Port uint16
+	// The inode of the socket.
+	Inode uint64
+}
+
+// ListeningPorts returns all listening TCP and UDP sockets with the processes
+// holding them open. A socket shared by several processes, e.g. the workers
+// of a pre-forking server, is returned once per process. Processes which
+// vanish during the scan are left out.
+func (fs FS) ListeningPorts() ([]ListeningPort, error) {
+	var (
+		ports = []ListeningPort{}
+		procs = map[int]*ListeningPort{}
+	)
+
+	err := fs.WalkNetSockets(func(s NetSocket) error {
+		if s.IP == nil {
+			return nil
+		}
+
+		port := ListeningPort{
+			Address: s.IP.LocalAddr,
+			Port:    s.IP.LocalPort,
+			Inode:   s.IP.Inode,
+		}
+		switch s.Table {
+		case "tcp", "tcp6":
+			if s.IP.State != TCPListen {
+				return nil
+			}
+			port.Protocol = "tcp"
+		case "udp", "udp6":
+			if s.IP.State != TCPClose || s.IP.RemPort != 0 {
+				return nil
+			}
+			port.Protocol = "udp"
+		default:
+			return nil
+		}
+		port.Family = "inet"
+		if s.Table == "tcp6" || s.Table == "udp6" {
+			port.Family = "inet6"
+		}
+
+		if len(s.Owners) == 0 {
+			ports = append(ports, port)
+			return nil
+		}
+
+		seen := map[int]bool{}
+		for _, o := range s.Owners {
+			if seen[o.PID] {
+				continue
+			}
+			seen[o.PID] = true
+
+			info, ok := procs[o.PID]
+			if !ok {
+				var err error
+				if info, err = fs.listeningPortProc(o.PID); err != nil {
+					return err
+				}
+				procs[o.PID] = info
+			}
+			if info == nil {
+				continue
+			}
+
+			p := port
+			p.Proc, p.Executable, p.Comm, p.CmdLine = info.Proc, info.Executable, info.Comm, info.CmdLine
+			ports = append(ports, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ports, nil
+}
+
+// listeningPortProc reads the process information of a ListeningPort. It
+// returns nil if the process vanished.
+func (fs FS) listeningPortProc(pid int) (*ListeningPort, error) {
+	p := Proc{PID: pid, fs: fs}
+
+	comm, err := p.Comm()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cmdline, err := p.CmdLine()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exe, err := p.Executable()
+	if err != nil && !os.IsPermission(err) {
+		return nil, err
+	}
+
+	return &ListeningPort{Proc: p, Executable: exe, Comm: comm, CmdLine: cmdline}, nil
+}
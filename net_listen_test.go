@@ -0,0 +1,50 @@
+package procfs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestListeningPorts(t *testing.T) {
+	ports, err := FS("fixtures").ListeningPorts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := make([]string, len(ports))
+	for i, p := range ports {
+		have[i] = fmt.Sprintf("%s %s [%s]:%d %d %d", p.Protocol, p.Family, p.Address, p.Port, p.Inode, p.Proc.PID)
+	}
+	sort.Strings(have)
+	want := []string{
+		"tcp inet [0.0.0.0]:22 1938 0",
+		"tcp inet [0.0.0.0]:80 2740 26233",
+		"tcp inet [127.0.0.1]:3306 1201 0",
+		"tcp inet6 [::1]:631 1777 0",
+		"tcp inet6 [::]:80 2742 26233",
+		"udp inet [0.0.0.0]:68 2743 0",
+		"udp inet [127.0.0.1]:123 1204 0",
+		"udp inet [127.0.0.1]:124 1203 0",
+		"udp inet6 [::]:547 1805 0",
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want listening ports %v, have %v", want, have)
+	}
+
+	for _, p := range ports {
+		if p.Proc.PID == 0 {
+			continue
+		}
+		if want, have := "/usr/sbin/nginx", p.Executable; want != have {
+			t.Errorf("want executable %s, have %s", want, have)
+		}
+		if want, have := "nginx", p.Comm; want != have {
+			t.Errorf("want comm %s, have %s", want, have)
+		}
+		if want, have := []string{"nginx: master process /usr/sbin/nginx"}, p.CmdLine; !reflect.DeepEqual(want, have) {
+			t.Errorf("want cmdline %v, have %v", want, have)
+		}
+	}
+}
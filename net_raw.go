@@ -0,0 +1,25 @@
+package procfs
+
+// WalkNetRaw calls fn for each socket in /proc/net/raw, without reading the
+// whole table into memory. It stops at the first error returned by fn. The
+// local port of raw sockets is the IP protocol number.
+func (fs FS) WalkNetRaw(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/raw"), true, fn)
+}
+
+// WalkNetRaw6 calls fn for each socket in /proc/net/raw6, without reading the
+// whole table into memory. It stops at the first error returned by fn. The
+// local port of raw sockets is the IP protocol number.
+func (fs FS) WalkNetRaw6(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/raw6"), true, fn)
+}
+
+// NetRaw returns all IPv4 raw sockets read from /proc/net/raw.
+func (fs FS) NetRaw() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetRaw)
+}
+
+// NetRaw6 returns all IPv6 raw sockets read from /proc/net/raw6.
+func (fs FS) NetRaw6() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetRaw6)
+}
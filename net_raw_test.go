@@ -0,0 +1,38 @@
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetRaw(t *testing.T) {
+	lines, err := FS("fixtures").NetRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(lines); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	testNetIPSocketLine(t, NetIPSocketLine{
+		Sl:        1,
+		LocalAddr: net.ParseIP("0.0.0.0"),
+		LocalPort: 1,
+		RemAddr:   net.ParseIP("0.0.0.0"),
+		State:     TCPClose,
+		Inode:     4120,
+		Drops:     3,
+	}, lines[0])
+}
+
+func TestNetRaw6(t *testing.T) {
+	lines, err := FS("fixtures").NetRaw6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(lines); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	if want, have := uint16(58), lines[0].LocalPort; want != have {
+		t.Errorf("want protocol %d, have %d", want, have)
+	}
+}
@@ -0,0 +1,264 @@
+package procfs
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetRouteFlags holds the RTF_* flags of a route.
+type NetRouteFlags uint32
+
+// Route flags as defined in include/uapi/linux/route.h and
+// include/uapi/linux/ipv6_route.h.
+const (
+	NetRouteFlagUp        NetRouteFlags = 0x0001
+	NetRouteFlagGateway   NetRouteFlags = 0x0002
+	NetRouteFlagHost      NetRouteFlags = 0x0004
+	NetRouteFlagReinstate NetRouteFlags = 0x0008
+	NetRouteFlagDynamic   NetRouteFlags = 0x0010
+	NetRouteFlagModified  NetRouteFlags = 0x0020
+	NetRouteFlagMTU       NetRouteFlags = 0x0040
+	NetRouteFlagWindow    NetRouteFlags = 0x0080
+	NetRouteFlagIRTT      NetRouteFlags = 0x0100
+	NetRouteFlagReject    NetRouteFlags = 0x0200
+	NetRouteFlagDefault   NetRouteFlags = 0x00010000
+	NetRouteFlagAddrconf  NetRouteFlags = 0x00040000
+	NetRouteFlagNoNextHop NetRouteFlags = 0x00200000
+	NetRouteFlagExpires   NetRouteFlags = 0x00400000
+	NetRouteFlagCache     NetRouteFlags = 0x01000000
+	NetRouteFlagLocal     NetRouteFlags = 0x80000000
+)
+
+// String returns the flags in the notation of route(8), e.g. "UG".
+func (f NetRouteFlags) String() string {
+	var s string
+	for _, n := range []struct {
+		flag NetRouteFlags
+		name string
+	}{
+		{NetRouteFlagUp, "U"},
+		{NetRouteFlagGateway, "G"},
+		{NetRouteFlagHost, "H"},
+		{NetRouteFlagReinstate, "R"},
+		{NetRouteFlagDynamic, "D"},
+		{NetRouteFlagModified, "M"},
+		{NetRouteFlagAddrconf, "A"},
+		{NetRouteFlagCache, "C"},
+		{NetRouteFlagReject, "!"},
+	} {
+		if f&n.flag != 0 {
+			s += n.name
+		}
+	}
+	return s
+}
+
+// NetRoute is a single IPv4 route read from /proc/net/route.
+type NetRoute struct {
+	// The interface the route uses.
+	Iface string
+	// The destination network or host.
+	Destination net.IP
+	// The gateway, 0.0.0.0 if none is needed.
+	Gateway net.IP
+	// The route flags.
+	Flags NetRouteFlags
+	// The number of references to the route.
+	RefCnt int
+	// The number of lookups of the route.
+	Use int
+	// The distance to the destination.
+	Metric int
+	// The netmask of the destination.
+	Mask net.IPMask
+	// The maximum segment size for TCP connections over the route.
+	MTU int
+	// The TCP window size for connections over the route.
+	Window int
+	// The initial round trip time for connections over the route.
+	IRTT int
+}
+
+// NetIPv6Route is a single IPv6 route read from /proc/net/ipv6_route.
+type NetIPv6Route struct {
+	// The destination network or host.
+	Destination net.IPNet
+	// The source network for source routing, ::/0 if unused.
+	Source net.IPNet
+	// The next hop, :: if none is needed.
+	NextHop net.IP
+	// The distance to the destination.
+	Metric uint32
+	// The number of references to the route.
+	RefCnt uint32
+	// The number of lookups of the route.
+	Use uint32
+	// The route flags.
+	Flags NetRouteFlags
+	// The interface the route uses.
+	Iface string
+}
+
+// NetRoute returns the IPv4 routing table read from /proc/net/route.
+func (fs FS) NetRoute() ([]NetRoute, error) {
+	f, err := os.Open(fs.Path("net/route"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		routes = []NetRoute{}
+		s      = bufio.NewScanner(f)
+	)
+	s.Scan() // skip header
+	for s.Scan() {
+		r, err := parseNetRoute(strings.Fields(s.Text()))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		routes = append(routes, r)
+	}
+
+	return routes, s.Err()
+}
+
+// NetIPv6Route returns the IPv6 routing table read from /proc/net/ipv6_route.
+func (fs FS) NetIPv6Route() ([]NetIPv6Route, error) {
+	f, err := os.Open(fs.Path("net/ipv6_route"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		routes = []NetIPv6Route{}
+		s      = bufio.NewScanner(f)
+	)
+	for s.Scan() {
+		r, err := parseNetIPv6Route(strings.Fields(s.Text()))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		routes = append(routes, r)
+	}
+
+	return routes, s.Err()
+}
+
+func parseNetRoute(fields []string) (NetRoute, error) {
+	if len(fields) != 11 {
+		return NetRoute{}, fmt.Errorf("expected 11 fields, have %d", len(fields))
+	}
+
+	var (
+		r   = NetRoute{Iface: fields[0]}
+		err error
+	)
+	if r.Destination, err = parseNetRouteIP(fields[1]); err != nil {
+		return NetRoute{}, err
+	}
+	if r.Gateway, err = parseNetRouteIP(fields[2]); err != nil {
+		return NetRoute{}, err
+	}
+	flags, err := strconv.ParseUint(fields[3], 16, 32)
+	if err != nil {
+		return NetRoute{}, err
+	}
+	r.Flags = NetRouteFlags(flags)
+	for i, v := range []*int{&r.RefCnt, &r.Use, &r.Metric} {
+		if *v, err = strconv.Atoi(fields[4+i]); err != nil {
+			return NetRoute{}, err
+		}
+	}
+	mask, err := parseNetRouteIP(fields[7])
+	if err != nil {
+		return NetRoute{}, err
+	}
+	r.Mask = net.IPMask(mask)
+	for i, v := range []*int{&r.MTU, &r.Window, &r.IRTT} {
+		if *v, err = strconv.Atoi(fields[8+i]); err != nil {
+			return NetRoute{}, err
+		}
+	}
+
+	return r, nil
+}
+
+// parseNetRouteIP parses an IPv4 address printed as a 32 bit word in host
+// byte order, e.g. "0101A8C0".
+func parseNetRouteIP(s string) (net.IP, error) {
+	if len(s) != 8 {
+		return nil, fmt.Errorf("invalid IP: %s", s)
+	}
+	ip, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return hostOrderIP(ip), nil
+}
+
+func parseNetIPv6Route(fields []string) (NetIPv6Route, error) {
+	if len(fields) != 10 {
+		return NetIPv6Route{}, fmt.Errorf("expected 10 fields, have %d", len(fields))
+	}
+
+	var (
+		r   = NetIPv6Route{Iface: fields[9]}
+		err error
+	)
+	if r.Destination, err = parseNetIPv6Net(fields[0], fields[1]); err != nil {
+		return NetIPv6Route{}, err
+	}
+	if r.Source, err = parseNetIPv6Net(fields[2], fields[3]); err != nil {
+		return NetIPv6Route{}, err
+	}
+	if r.NextHop, err = parseNetIPv6(fields[4]); err != nil {
+		return NetIPv6Route{}, err
+	}
+	for i, v := range []*uint32{&r.Metric, &r.RefCnt, &r.Use} {
+		if *v, err = parseHexUint32(fields[5+i]); err != nil {
+			return NetIPv6Route{}, err
+		}
+	}
+	flags, err := parseHexUint32(fields[8])
+	if err != nil {
+		return NetIPv6Route{}, err
+	}
+	r.Flags = NetRouteFlags(flags)
+
+	return r, nil
+}
+
+// parseNetIPv6Net parses an IPv6 network from an address printed in network
+// byte order and a hexadecimal prefix length.
+func parseNetIPv6Net(addr, prefix string) (net.IPNet, error) {
+	ip, err := parseNetIPv6(addr)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	ones, err := strconv.ParseUint(prefix, 16, 8)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	if ones > 128 {
+		return net.IPNet{}, fmt.Errorf("invalid prefix length: %s", prefix)
+	}
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(int(ones), 128)}, nil
+}
+
+func parseNetIPv6(s string) (net.IP, error) {
+	if len(s) != 32 {
+		return nil, fmt.Errorf("invalid IP: %s", s)
+	}
+	ip, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return net.IP(ip), nil
+}
@@ -0,0 +1,102 @@
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetRoute(t *testing.T) {
+	routes, err := FS("fixtures").NetRoute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 5, len(routes); want != have {
+		t.Fatalf("want %d routes, have %d", want, have)
+	}
+
+	for i, want := range []struct {
+		iface, dst, gw, mask, flags string
+		metric, use, mtu            int
+	}{
+		{"eth0", "0.0.0.0", "192.168.1.1", "00000000", "UG", 100, 0, 0},
+		{"eth0", "192.168.1.0", "0.0.0.0", "ffffff00", "U", 100, 0, 0},
+		{"docker0", "172.17.0.0", "0.0.0.0", "ffff0000", "U", 0, 0, 0},
+		{"eth0", "10.10.10.10", "192.168.1.1", "ffffffff", "UGH", 0, 12, 1400},
+		{"lo", "10.0.0.0", "0.0.0.0", "ff000000", "U!", 0, 0, 0},
+	} {
+		r := routes[i]
+		if r.Iface != want.iface {
+			t.Errorf("%d: want iface %s, have %s", i, want.iface, r.Iface)
+		}
+		if !r.Destination.Equal(net.ParseIP(want.dst)) {
+			t.Errorf("%d: want destination %s, have %s", i, want.dst, r.Destination)
+		}
+		if !r.Gateway.Equal(net.ParseIP(want.gw)) {
+			t.Errorf("%d: want gateway %s, have %s", i, want.gw, r.Gateway)
+		}
+		if r.Mask.String() != want.mask {
+			t.Errorf("%d: want mask %s, have %s", i, want.mask, r.Mask)
+		}
+		if r.Flags.String() != want.flags {
+			t.Errorf("%d: want flags %s, have %s", i, want.flags, r.Flags)
+		}
+		if r.Metric != want.metric || r.Use != want.use || r.MTU != want.mtu {
+			t.Errorf("%d: want metric %d, use %d, mtu %d, have %d, %d, %d", i, want.metric, want.use, want.mtu, r.Metric, r.Use, r.MTU)
+		}
+	}
+}
+
+func TestNetIPv6Route(t *testing.T) {
+	routes, err := FS("fixtures").NetIPv6Route()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 5, len(routes); want != have {
+		t.Fatalf("want %d routes, have %d", want, have)
+	}
+
+	for i, want := range []struct {
+		dst, src, nextHop, iface string
+		metric, refCnt           uint32
+		flags                    NetRouteFlags
+	}{
+		{"::/0", "::/0", "fe80::1", "eth0", 1024, 1, NetRouteFlagUp | NetRouteFlagGateway | NetRouteFlagDefault | NetRouteFlagAddrconf | NetRouteFlagExpires},
+		{"2001:db8::/64", "::/0", "::", "eth0", 256, 1, NetRouteFlagUp},
+		{"fe80::/64", "::/0", "::", "eth0", 256, 1, NetRouteFlagUp},
+		{"::1/128", "::/0", "::", "lo", 0, 3, NetRouteFlagUp | NetRouteFlagNoNextHop | NetRouteFlagLocal},
+		{"::/0", "::/0", "::", "lo", 0xffffffff, 1, NetRouteFlagReject | NetRouteFlagNoNextHop},
+	} {
+		r := routes[i]
+		if r.Destination.String() != want.dst {
+			t.Errorf("%d: want destination %s, have %s", i, want.dst, r.Destination.String())
+		}
+		if r.Source.String() != want.src {
+			t.Errorf("%d: want source %s, have %s", i, want.src, r.Source.String())
+		}
+		if !r.NextHop.Equal(net.ParseIP(want.nextHop)) {
+			t.Errorf("%d: want next hop %s, have %s", i, want.nextHop, r.NextHop)
+		}
+		if r.Iface != want.iface {
+			t.Errorf("%d: want iface %s, have %s", i, want.iface, r.Iface)
+		}
+		if r.Metric != want.metric || r.RefCnt != want.refCnt {
+			t.Errorf("%d: want metric %d, refcnt %d, have %d, %d", i, want.metric, want.refCnt, r.Metric, r.RefCnt)
+		}
+		if r.Flags != want.flags {
+			t.Errorf("%d: want flags %#x, have %#x", i, want.flags, r.Flags)
+		}
+	}
+}
+
+func TestParseNetRouteInvalid(t *testing.T) {
+	for _, fields := range [][]string{
+		{"eth0", "00000000", "0101A8C0", "0003", "0", "0", "100", "00000000", "0", "0"},
+		{"eth0", "0000000", "0101A8C0", "0003", "0", "0", "100", "00000000", "0", "0", "0"},
+		{"eth0", "00000000", "0101A8CG", "0003", "0", "0", "100", "00000000", "0", "0", "0"},
+		{"eth0", "00000000", "0101A8C0", "0003", "0", "x", "100", "00000000", "0", "0", "0"},
+	} {
+		if _, err := parseNetRoute(fields); err == nil {
+			t.Errorf("want error for %v", fields)
+		}
+	}
+}
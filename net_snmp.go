@@ -0,0 +1,245 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetProtoStats holds the counters of files like /proc/net/snmp, keyed by
+// protocol, e.g. "Tcp", and counter name, e.g. "RetransSegs".
+type NetProtoStats map[string]map[string]int64
+
+// NetSNMP holds the IP, ICMP, TCP and UDP counters read from /proc/net/snmp.
+type NetSNMP struct {
+	// Input IP datagrams discarded for lack of buffer space or similar.
+	IPInDiscards uint64
+	// Failures detected by the IP reassembly algorithm.
+	IPReasmFails uint64
+	// IP datagrams that needed to be fragmented, but couldn't be.
+	IPFragFails uint64
+	// TCP connections which went to SYN_SENT from CLOSED.
+	TCPActiveOpens uint64
+	// TCP connections which went to SYN_RECV from LISTEN.
+	TCPPassiveOpens uint64
+	// TCP connections currently in ESTABLISHED or CLOSE_WAIT.
+	TCPCurrEstab uint64
+	// TCP segments retransmitted.
+	TCPRetransSegs uint64
+	// TCP segments received in error.
+	TCPInErrs uint64
+	// UDP datagrams delivered to users.
+	UDPInDatagrams uint64
+	// UDP datagrams received for ports without a listener.
+	UDPNoPorts uint64
+	// UDP datagrams which couldn't be delivered for reasons other than a
+	// missing listener.
+	UDPInErrors uint64
+	// UDP datagrams dropped because the receive buffer was full.
+	UDPRcvbufErrors uint64
+	// UDP datagrams dropped because the send buffer was full.
+	UDPSndbufErrors uint64
+
+	// All counters by protocol and name.
+	Stats NetProtoStats
+}
+
+// NetNetstat holds the extended IP and TCP counters read from
+// /proc/net/netstat.
+type NetNetstat struct {
+	// SYN cookies sent.
+	SyncookiesSent uint64
+	// Valid SYN cookies received.
+	SyncookiesRecv uint64
+	// Invalid SYN cookies received.
+	SyncookiesFailed uint64
+	// Times the accept queue of a listening socket overflowed.
+	ListenOverflows uint64
+	// SYNs to listening sockets which were dropped.
+	ListenDrops uint64
+	// TCP retransmission timeouts.
+	TCPTimeouts uint64
+	// Packets dropped because the socket backlog was full.
+	TCPBacklogDrop uint64
+
+	// All counters by protocol and name.
+	Stats NetProtoStats
+}
+
+// NetSNMP6 holds the IPv6, ICMPv6 and UDPv6 counters read from
+// /proc/net/snmp6.
+type NetSNMP6 struct {
+	// Input IPv6 datagrams discarded for lack of buffer space or similar.
+	IP6InDiscards uint64
+	// Failures detected by the IPv6 reassembly algorithm.
+	IP6ReasmFails uint64
+	// UDP datagrams delivered to users.
+	UDP6InDatagrams uint64
+	// UDP datagrams received for ports without a listener.
+	UDP6NoPorts uint64
+	// UDP datagrams which couldn't be delivered for reasons other than a
+	// missing listener.
+	UDP6InErrors uint64
+	// UDP datagrams dropped because the receive buffer was full.
+	UDP6RcvbufErrors uint64
+
+	// All counters by name, e.g. "Ip6InReceives".
+	Stats map[string]int64
+}
+
+// NetSNMP returns the protocol counters read from /proc/net/snmp.
+func (fs FS) NetSNMP() (NetSNMP, error) {
+	stats, err := newNetProtoStats(fs.Path("net/snmp"))
+	if err != nil {
+		return NetSNMP{}, err
+	}
+
+	s := NetSNMP{Stats: stats}
+	for _, c := range []struct {
+		v     *uint64
+		proto string
+		name  string
+	}{
+		{&s.IPInDiscards, "Ip", "InDiscards"},
+		{&s.IPReasmFails, "Ip", "ReasmFails"},
+		{&s.IPFragFails, "Ip", "FragFails"},
+		{&s.TCPActiveOpens, "Tcp", "ActiveOpens"},
+		{&s.TCPPassiveOpens, "Tcp", "PassiveOpens"},
+		{&s.TCPCurrEstab, "Tcp", "CurrEstab"},
+		{&s.TCPRetransSegs, "Tcp", "RetransSegs"},
+		{&s.TCPInErrs, "Tcp", "InErrs"},
+		{&s.UDPInDatagrams, "Udp", "InDatagrams"},
+		{&s.UDPNoPorts, "Udp", "NoPorts"},
+		{&s.UDPInErrors, "Udp", "InErrors"},
+		{&s.UDPRcvbufErrors, "Udp", "RcvbufErrors"},
+		{&s.UDPSndbufErrors, "Udp", "SndbufErrors"},
+	} {
+		*c.v = uint64(stats[c.proto][c.name])
+	}
+
+	return s, nil
+}
+
+// NetNetstat returns the extended protocol counters read from
+// /proc/net/netstat.
+func (fs FS) NetNetstat() (NetNetstat, error) {
+	stats, err := newNetProtoStats(fs.Path("net/netstat"))
+	if err != nil {
+		return NetNetstat{}, err
+	}
+
+	n := NetNetstat{Stats: stats}
+	for _, c := range []struct {
+		v    *uint64
+		name string
+	}{
+		{&n.SyncookiesSent, "SyncookiesSent"},
+		{&n.SyncookiesRecv, "SyncookiesRecv"},
+		{&n.SyncookiesFailed, "SyncookiesFailed"},
+		{&n.ListenOverflows, "ListenOverflows"},
+		{&n.ListenDrops, "ListenDrops"},
+		{&n.TCPTimeouts, "TCPTimeouts"},
+		{&n.TCPBacklogDrop, "TCPBacklogDrop"},
+	} {
+		*c.v = uint64(stats["TcpExt"][c.name])
+	}
+
+	return n, nil
+}
+
+// NetSNMP6 returns the IPv6 protocol counters read from /proc/net/snmp6.
+func (fs FS) NetSNMP6() (NetSNMP6, error) {
+	f, err := os.Open(fs.Path("net/snmp6"))
+	if err != nil {
+		return NetSNMP6{}, err
+	}
+	defer f.Close()
+
+	var (
+		stats = map[string]int64{}
+		s     = bufio.NewScanner(f)
+	)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return NetSNMP6{}, fmt.Errorf("couldn't parse %s line %s", f.Name(), s.Text())
+		}
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return NetSNMP6{}, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		stats[fields[0]] = v
+	}
+	if err := s.Err(); err != nil {
+		return NetSNMP6{}, err
+	}
+
+	n := NetSNMP6{Stats: stats}
+	for _, c := range []struct {
+		v    *uint64
+		name string
+	}{
+		{&n.IP6InDiscards, "Ip6InDiscards"},
+		{&n.IP6ReasmFails, "Ip6ReasmFails"},
+		{&n.UDP6InDatagrams, "Udp6InDatagrams"},
+		{&n.UDP6NoPorts, "Udp6NoPorts"},
+		{&n.UDP6InErrors, "Udp6InErrors"},
+		{&n.UDP6RcvbufErrors, "Udp6RcvbufErrors"},
+	} {
+		*c.v = uint64(stats[c.name])
+	}
+
+	return n, nil
+}
+
+// newNetProtoStats parses files consisting of pairs of lines, the first
+// holding the counter names and the second their values, both prefixed by
+// the protocol name:
+//
+//	Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ...
+//	Tcp: 1 200 120000 -1 ...
+func newNetProtoStats(file string) (NetProtoStats, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		stats = NetProtoStats{}
+		s     = bufio.NewScanner(f)
+	)
+	for s.Scan() {
+		names := strings.Fields(s.Text())
+		if len(names) == 0 {
+			continue
+		}
+		if !s.Scan() {
+			return nil, fmt.Errorf("couldn't parse %s: missing values for %s", f.Name(), names[0])
+		}
+		values := strings.Fields(s.Text())
+
+		if len(names) != len(values) || names[0] != values[0] || !strings.HasSuffix(names[0], ":") {
+			return nil, fmt.Errorf("couldn't parse %s: mismatched lines %s", f.Name(), names[0])
+		}
+
+		proto := strings.TrimSuffix(names[0], ":")
+		if stats[proto] == nil {
+			stats[proto] = map[string]int64{}
+		}
+		for i := 1; i < len(names); i++ {
+			v, err := strconv.ParseInt(values[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s %s %s: %s", f.Name(), proto, names[i], err)
+			}
+			stats[proto][names[i]] = v
+		}
+	}
+
+	return stats, s.Err()
+}
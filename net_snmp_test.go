@@ -0,0 +1,121 @@
+package procfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetSNMP(t *testing.T) {
+	s, err := FS("fixtures").NetSNMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "IPReasmFails", want: 3, have: s.IPReasmFails},
+		{name: "TCPActiveOpens", want: 10471, have: s.TCPActiveOpens},
+		{name: "TCPCurrEstab", want: 31, have: s.TCPCurrEstab},
+		{name: "TCPRetransSegs", want: 4213, have: s.TCPRetransSegs},
+		{name: "UDPInErrors", want: 1581, have: s.UDPInErrors},
+		{name: "UDPRcvbufErrors", want: 1575, have: s.UDPRcvbufErrors},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := int64(-1), s.Stats["Tcp"]["MaxConn"]; want != have {
+		t.Errorf("want Tcp MaxConn %d, have %d", want, have)
+	}
+	if want, have := int64(50), s.Stats["IcmpMsg"]["OutType3"]; want != have {
+		t.Errorf("want IcmpMsg OutType3 %d, have %d", want, have)
+	}
+	if want, have := 6, len(s.Stats); want != have {
+		t.Errorf("want %d protocols, have %d", want, have)
+	}
+}
+
+func TestNetNetstat(t *testing.T) {
+	n, err := FS("fixtures").NetNetstat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "SyncookiesSent", want: 13, have: n.SyncookiesSent},
+		{name: "SyncookiesRecv", want: 11, have: n.SyncookiesRecv},
+		{name: "SyncookiesFailed", want: 2, have: n.SyncookiesFailed},
+		{name: "ListenOverflows", want: 112, have: n.ListenOverflows},
+		{name: "ListenDrops", want: 117, have: n.ListenDrops},
+		{name: "TCPTimeouts", want: 503, have: n.TCPTimeouts},
+		{name: "TCPBacklogDrop", want: 4, have: n.TCPBacklogDrop},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := int64(3987226387), n.Stats["IpExt"]["InOctets"]; want != have {
+		t.Errorf("want IpExt InOctets %d, have %d", want, have)
+	}
+}
+
+func TestNetSNMP6(t *testing.T) {
+	s, err := FS("fixtures").NetSNMP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "IP6InDiscards", want: 7, have: s.IP6InDiscards},
+		{name: "IP6ReasmFails", want: 3, have: s.IP6ReasmFails},
+		{name: "UDP6InDatagrams", want: 2107, have: s.UDP6InDatagrams},
+		{name: "UDP6InErrors", want: 34, have: s.UDP6InErrors},
+		{name: "UDP6RcvbufErrors", want: 30, have: s.UDP6RcvbufErrors},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := int64(4), s.Stats["Icmp6InType133"]; want != have {
+		t.Errorf("want Icmp6InType133 %d, have %d", want, have)
+	}
+}
+
+func TestNetProtoStatsInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, content := range []string{
+		"Tcp: RtoAlgorithm RtoMin\n",
+		"Tcp: RtoAlgorithm RtoMin\nTcp: 1\n",
+		"Tcp: RtoAlgorithm RtoMin\nUdp: 1 200\n",
+		"Tcp: RtoAlgorithm RtoMin\nTcp: 1 foo\n",
+	} {
+		file := filepath.Join(dir, "snmp")
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newNetProtoStats(file); err == nil {
+			t.Errorf("want error for %q", content)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package procfs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SocketOwner is a file descriptor of a process referring to a socket.
+type SocketOwner struct {
+	// The process ID.
+	PID int
+	// The file descriptor number.
+	FD uintptr
+}
+
+// SocketIndex maps socket inodes to the file descriptors referring to them.
+type SocketIndex map[uint64][]SocketOwner
+
+// NetSocket is a socket from one of the /proc/net socket tables along with the
+// processes holding it open.
+type NetSocket struct {
+	// The table the socket was read from, e.g. "tcp6" or "unix".
+	Table string
+	// The socket, if read from one of the IP socket tables.
+	IP *NetIPSocketLine
+	// The socket, if read from /proc/net/unix.
+	Unix *NetUnixLine
+	// The file descriptors referring to the socket. Empty for sockets which
+	// are not held open by any process visible to the caller.
+	Owners []SocketOwner
+}
+
+// NewSocketIndex scans the file descriptors of all processes for sockets.
+// Processes which vanish during the scan or whose file descriptors can't be
+// read due to missing permissions are left out.
+func (fs FS) NewSocketIndex() (SocketIndex, error) {
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := SocketIndex{}
+	for _, p := range procs {
+		if err := idx.add(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return idx, nil
+}
+
+func (idx SocketIndex) add(p Proc) error {
+	names, err := p.fileDescriptors()
+	if os.IsNotExist(err) || os.IsPermission(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		target, err := os.Readlink(p.path("fd", name))
+		if os.IsNotExist(err) || os.IsPermission(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(target, "socket:") {
+			continue
+		}
+
+		inode := parseFDInode(strings.TrimPrefix(target, "socket:"))
+		fd, err := strconv.ParseUint(name, 10, 32)
+		if inode == 0 || err != nil {
+			continue
+		}
+		idx[inode] = append(idx[inode], SocketOwner{PID: p.PID, FD: uintptr(fd)})
+	}
+
+	return nil
+}
+
+// WalkNetSockets calls fn for each socket in the TCP, UDP, UDP-Lite, raw and
+// unix socket tables, joined with the processes holding it open. Tables which
+// don't exist, e.g. for protocols not supported by the kernel, are skipped.
+// It stops at the first error returned by fn.
+func (fs FS) WalkNetSockets(fn func(NetSocket) error) error {
+	idx, err := fs.NewSocketIndex()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range []struct {
+		name string
+		walk func(func(NetIPSocketLine) error) error
+	}{
+		{"tcp", fs.WalkNetTCP},
+		{"tcp6", fs.WalkNetTCP6},
+		{"udp", fs.WalkNetUDP},
+		{"udp6", fs.WalkNetUDP6},
+		{"udplite", fs.WalkNetUDPLite},
+		{"udplite6", fs.WalkNetUDPLite6},
+		{"raw", fs.WalkNetRaw},
+		{"raw6", fs.WalkNetRaw6},
+	} {
+		var fnErr error
+		err := t.walk(func(l NetIPSocketLine) error {
+			fnErr = fn(NetSocket{Table: t.name, IP: &l, Owners: idx.owners(l.Inode)})
+			return fnErr
+		})
+		if fnErr != nil {
+			return fnErr
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	var fnErr error
+	err = fs.WalkNetUnix(func(l NetUnixLine) error {
+		fnErr = fn(NetSocket{Table: "unix", Unix: &l, Owners: idx.owners(l.Inode)})
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// NetSockets returns all sockets in the TCP, UDP, UDP-Lite, raw and unix
+// socket tables, joined with the processes holding them open.
+func (fs FS) NetSockets() ([]NetSocket, error) {
+	sockets := []NetSocket{}
+	err := fs.WalkNetSockets(func(s NetSocket) error {
+		sockets = append(sockets, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sockets, nil
+}
+
+// owners returns the file descriptors referring to the socket with the given
+// inode. Sockets in TIME_WAIT have inode 0 and are never owned.
+func (idx SocketIndex) owners(inode uint64) []SocketOwner {
+	if inode == 0 {
+		return nil
+	}
+	return idx[inode]
+}
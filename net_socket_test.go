@@ -0,0 +1,69 @@
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSocketIndex(t *testing.T) {
+	idx, err := FS("fixtures").NewSocketIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := SocketIndex{
+		2740: {{PID: 26233, FD: 3}},
+		2741: {{PID: 26233, FD: 4}},
+		2742: {{PID: 26233, FD: 11}},
+		2745: {{PID: 26233, FD: 12}},
+	}
+	if !reflect.DeepEqual(want, idx) {
+		t.Errorf("want socket index %v, have %v", want, idx)
+	}
+}
+
+func TestSocketIndexVanishedProc(t *testing.T) {
+	idx := SocketIndex{}
+	if err := idx.add(Proc{PID: 99999, fs: FS("fixtures")}); err != nil {
+		t.Errorf("want vanished process to be skipped, have %s", err)
+	}
+	if len(idx) != 0 {
+		t.Errorf("want empty socket index, have %v", idx)
+	}
+}
+
+func TestNetSockets(t *testing.T) {
+	sockets, err := FS("fixtures").NetSockets()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		tables = map[string]int{}
+		owned  = map[string][]uint64{}
+	)
+	for _, s := range sockets {
+		tables[s.Table]++
+		if (s.IP == nil) == (s.Unix == nil) {
+			t.Errorf("want exactly one of IP and unix socket for %s, have %v and %v", s.Table, s.IP, s.Unix)
+			continue
+		}
+		if len(s.Owners) == 0 {
+			continue
+		}
+		if s.IP != nil {
+			owned[s.Table] = append(owned[s.Table], s.IP.Inode)
+		} else {
+			owned[s.Table] = append(owned[s.Table], s.Unix.Inode)
+		}
+	}
+
+	wantTables := map[string]int{"tcp": 5, "tcp6": 3, "udp": 4, "udp6": 1, "raw": 1, "raw6": 1, "unix": 6}
+	if !reflect.DeepEqual(wantTables, tables) {
+		t.Errorf("want sockets per table %v, have %v", wantTables, tables)
+	}
+	wantOwned := map[string][]uint64{"tcp": {2740, 2741}, "tcp6": {2742}, "unix": {2745}}
+	if !reflect.DeepEqual(wantOwned, owned) {
+		t.Errorf("want owned sockets %v, have %v", wantOwned, owned)
+	}
+}
@@ -0,0 +1,104 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetSockstat holds the socket usage read from /proc/net/sockstat or
+// /proc/net/sockstat6.
+type NetSockstat struct {
+	// The number of sockets in use. Only available in /proc/net/sockstat.
+	Used int
+	// The usage per protocol, keyed by the protocol name, e.g. "TCP" or
+	// "UDP6".
+	Protocols map[string]NetSockstatProtocol
+}
+
+// NetSockstatProtocol holds the socket usage of a single protocol. Counters
+// which aren't reported for the protocol are 0.
+type NetSockstatProtocol struct {
+	// The protocol name.
+	Protocol string
+	// Sockets in use.
+	InUse int
+	// Orphaned sockets, i.e. sockets no longer attached to a file descriptor.
+	Orphan int
+	// Sockets in TIME_WAIT.
+	TW int
+	// Allocated sockets.
+	Alloc int
+	// Memory used by the sockets of the protocol in bytes.
+	Mem int64
+}
+
+// NetSockstat returns the IPv4 socket usage read from /proc/net/sockstat.
+func (fs FS) NetSockstat() (NetSockstat, error) {
+	return newNetSockstat(fs.Path("net/sockstat"))
+}
+
+// NetSockstat6 returns the IPv6 socket usage read from /proc/net/sockstat6.
+func (fs FS) NetSockstat6() (NetSockstat, error) {
+	return newNetSockstat(fs.Path("net/sockstat6"))
+}
+
+func newNetSockstat(file string) (NetSockstat, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return NetSockstat{}, err
+	}
+	defer f.Close()
+
+	var (
+		st = NetSockstat{Protocols: map[string]NetSockstatProtocol{}}
+		s  = bufio.NewScanner(f)
+	)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields)%2 != 1 || !strings.HasSuffix(fields[0], ":") {
+			return NetSockstat{}, fmt.Errorf("couldn't parse %s line %s", f.Name(), s.Text())
+		}
+
+		var (
+			name = strings.TrimSuffix(fields[0], ":")
+			p    = NetSockstatProtocol{Protocol: name}
+		)
+		for i := 1; i < len(fields); i += 2 {
+			v, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return NetSockstat{}, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+			}
+
+			switch fields[i] {
+			case "used":
+				st.Used = int(v)
+			case "inuse":
+				p.InUse = int(v)
+			case "orphan":
+				p.Orphan = int(v)
+			case "tw":
+				p.TW = int(v)
+			case "alloc":
+				p.Alloc = int(v)
+			case "mem":
+				// Reported in pages.
+				p.Mem = v * int64(os.Getpagesize())
+			case "memory":
+				// Reported in bytes.
+				p.Mem = v
+			}
+		}
+
+		if name != "sockets" {
+			st.Protocols[name] = p
+		}
+	}
+
+	return st, s.Err()
+}
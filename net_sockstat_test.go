@@ -0,0 +1,53 @@
+package procfs
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNetSockstat(t *testing.T) {
+	s, err := FS("fixtures").NetSockstat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 1602, s.Used; want != have {
+		t.Errorf("want used sockets %d, have %d", want, have)
+	}
+
+	page := int64(os.Getpagesize())
+	for _, want := range []NetSockstatProtocol{
+		{Protocol: "TCP", InUse: 35, Orphan: 0, TW: 4, Alloc: 59, Mem: 22 * page},
+		{Protocol: "UDP", InUse: 12, Mem: 62 * page},
+		{Protocol: "UDPLITE"},
+		{Protocol: "RAW"},
+		{Protocol: "FRAG", InUse: 1, Mem: 4352},
+	} {
+		if have := s.Protocols[want.Protocol]; want != have {
+			t.Errorf("want %+v, have %+v", want, have)
+		}
+	}
+	if want, have := 5, len(s.Protocols); want != have {
+		t.Errorf("want %d protocols, have %d", want, have)
+	}
+}
+
+func TestNetSockstat6(t *testing.T) {
+	s, err := FS("fixtures").NetSockstat6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 0, s.Used; want != have {
+		t.Errorf("want used sockets %d, have %d", want, have)
+	}
+	for _, want := range []NetSockstatProtocol{
+		{Protocol: "TCP6", InUse: 17},
+		{Protocol: "UDP6", InUse: 9},
+		{Protocol: "RAW6", InUse: 1},
+	} {
+		if have := s.Protocols[want.Protocol]; want != have {
+			t.Errorf("want %+v, have %+v", want, have)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SoftnetStat holds the per-CPU packet processing statistics of the network
+// stack, read from /proc/net/softnet_stat.
+type SoftnetStat struct {
+	// The CPU the statistics belong to. Kernels before 5.10 don't print the
+	// CPU, in which case it is the line number, which differs from the CPU if
+	// CPUs are offline.
+	CPU int
+	// Packets processed by the softirq handler.
+	Processed uint32
+	// Packets dropped because the backlog queue was full.
+	Dropped uint32
+	// Times the softirq handler ran out of budget or time with work
+	// remaining.
+	TimeSqueeze uint32
+	// Times a lock collision occurred when transmitting. Always 0 since
+	// Linux 4.9.
+	CPUCollision uint32
+	// Times the CPU was woken up by an inter-processor interrupt to process
+	// packets steered to it by RPS. Available since Linux 2.6.35.
+	ReceivedRps uint32
+	// Packets dropped by the flow limit. Available since Linux 3.11.
+	FlowLimitCount uint32
+	// The length of the backlog queues. Available since Linux 5.10.
+	BacklogLen uint32
+}
+
+// NetSoftnetStat returns the per-CPU statistics read from
+// /proc/net/softnet_stat.
+func (fs FS) NetSoftnetStat() ([]SoftnetStat, error) {
+	f, err := os.Open(fs.Path("net/softnet_stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		stats = []SoftnetStat{}
+		s     = bufio.NewScanner(f)
+	)
+	for cpu := 0; s.Scan(); cpu++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 9 {
+			return nil, fmt.Errorf("couldn't parse %s line %s: expected at least 9 fields, have %d", f.Name(), s.Text(), len(fields))
+		}
+
+		values := make([]uint32, len(fields))
+		for i, field := range fields {
+			v, err := strconv.ParseUint(field, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+			}
+			values[i] = uint32(v)
+		}
+
+		st := SoftnetStat{
+			CPU:          cpu,
+			Processed:    values[0],
+			Dropped:      values[1],
+			TimeSqueeze:  values[2],
+			CPUCollision: values[8],
+		}
+		if len(values) > 9 {
+			st.ReceivedRps = values[9]
+		}
+		if len(values) > 10 {
+			st.FlowLimitCount = values[10]
+		}
+		if len(values) > 11 {
+			st.BacklogLen = values[11]
+		}
+		if len(values) > 12 {
+			st.CPU = int(values[12])
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, s.Err()
+}
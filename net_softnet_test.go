@@ -0,0 +1,82 @@
+package procfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNetSoftnetStat(t *testing.T) {
+	stats, err := FS("fixtures").NetSoftnetStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SoftnetStat{
+		{CPU: 0, Processed: 0x15c73, Dropped: 0x20e76, TimeSqueeze: 0xf0000769},
+		{CPU: 1, Processed: 0x1663fb2, TimeSqueeze: 0x109a4, ReceivedRps: 0x2a, FlowLimitCount: 3},
+		{CPU: 3, Processed: 0xa4f3c6, Dropped: 0x1b, TimeSqueeze: 0xe5e, ReceivedRps: 0x11, BacklogLen: 2},
+	}
+	if !reflect.DeepEqual(want, stats) {
+		t.Errorf("want %+v, have %+v", want, stats)
+	}
+}
+
+func TestNetSoftnetStatColumns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "net"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		content string
+		want    []SoftnetStat
+		invalid bool
+	}{
+		{
+			// Linux 2.6.32
+			content: "0000000a 00000001 00000002 00000000 00000000 00000000 00000000 00000000 00000003\n" +
+				"0000000b 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000\n",
+			want: []SoftnetStat{
+				{CPU: 0, Processed: 10, Dropped: 1, TimeSqueeze: 2, CPUCollision: 3},
+				{CPU: 1, Processed: 11},
+			},
+		},
+		{
+			// Linux 3.10
+			content: "0000000a 00000001 00000002 00000000 00000000 00000000 00000000 00000000 00000000 00000004\n",
+			want:    []SoftnetStat{{CPU: 0, Processed: 10, Dropped: 1, TimeSqueeze: 2, ReceivedRps: 4}},
+		},
+		{
+			content: "0000000a 00000001 00000002\n",
+			invalid: true,
+		},
+		{
+			content: "0000000a 00000001 00000002 00000000 00000000 00000000 00000000 00000000 0000000g\n",
+			invalid: true,
+		},
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, "net", "softnet_stat"), []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stats, err := FS(dir).NetSoftnetStat()
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("want error for %q", tt.content)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, stats) {
+			t.Errorf("want %+v, have %+v", tt.want, stats)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package procfs
+
+// WalkNetTCP calls fn for each socket in /proc/net/tcp, without reading the
+// whole table into memory. It stops at the first error returned by fn.
+func (fs FS) WalkNetTCP(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/tcp"), false, fn)
+}
+
+// WalkNetTCP6 calls fn for each socket in /proc/net/tcp6, without reading the
+// whole table into memory. It stops at the first error returned by fn.
+func (fs FS) WalkNetTCP6(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/tcp6"), false, fn)
+}
+
+// NetTCP returns all IPv4 TCP sockets read from /proc/net/tcp.
+func (fs FS) NetTCP() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetTCP)
+}
+
+// NetTCP6 returns all IPv6 TCP sockets read from /proc/net/tcp6.
+func (fs FS) NetTCP6() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetTCP6)
+}
@@ -0,0 +1,148 @@
+package procfs
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestNetTCP(t *testing.T) {
+	lines, err := FS("fixtures").NetTCP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 5, len(lines); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	want := NetIPSocketLine{
+		Sl:           3,
+		LocalAddr:    net.ParseIP("192.168.1.10"),
+		LocalPort:    80,
+		RemAddr:      net.ParseIP("192.168.1.20"),
+		RemPort:      51234,
+		State:        TCPEstablished,
+		TimerActive:  2,
+		TimerExpires: 0xafe58,
+		UID:          33,
+		Inode:        2741,
+	}
+	testNetIPSocketLine(t, want, lines[3])
+
+	want = NetIPSocketLine{
+		Sl:           4,
+		LocalAddr:    net.ParseIP("192.168.1.10"),
+		LocalPort:    22,
+		RemAddr:      net.ParseIP("192.168.1.30"),
+		RemPort:      54321,
+		State:        TCPEstablished,
+		TxQueue:      36,
+		TimerActive:  1,
+		TimerExpires: 20,
+		Retransmits:  2,
+		Inode:        3122,
+	}
+	testNetIPSocketLine(t, want, lines[4])
+
+	if want, have := "LISTEN", lines[0].State.String(); want != have {
+		t.Errorf("want state %s, have %s", want, have)
+	}
+	if !lines[2].LocalAddr.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("want local address 127.0.0.1, have %s", lines[2].LocalAddr)
+	}
+}
+
+func TestNetTCP6(t *testing.T) {
+	lines, err := FS("fixtures").NetTCP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 3, len(lines); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	testNetIPSocketLine(t, NetIPSocketLine{
+		Sl:        1,
+		LocalAddr: net.ParseIP("::1"),
+		LocalPort: 631,
+		RemAddr:   net.ParseIP("::"),
+		State:     TCPListen,
+		Inode:     1777,
+	}, lines[1])
+	testNetIPSocketLine(t, NetIPSocketLine{
+		Sl:           2,
+		LocalAddr:    net.ParseIP("::ffff:192.168.1.10"),
+		LocalPort:    80,
+		RemAddr:      net.ParseIP("::ffff:192.168.1.20"),
+		RemPort:      51236,
+		State:        TCPTimeWait,
+		TimerActive:  3,
+		TimerExpires: 0xf9c,
+	}, lines[2])
+}
+
+func TestWalkNetTCPStop(t *testing.T) {
+	var (
+		n    int
+		stop = errors.New("stop")
+	)
+	err := FS("fixtures").WalkNetTCP(func(NetIPSocketLine) error {
+		n++
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("want error %v, have %v", stop, err)
+	}
+	if want, have := 2, n; want != have {
+		t.Errorf("want %d sockets walked, have %d", want, have)
+	}
+}
+
+func testNetIPSocketLine(t *testing.T, want, have NetIPSocketLine) {
+	if !want.LocalAddr.Equal(have.LocalAddr) || !want.RemAddr.Equal(have.RemAddr) {
+		t.Errorf("want addresses %s -> %s, have %s -> %s", want.LocalAddr, want.RemAddr, have.LocalAddr, have.RemAddr)
+	}
+	want.LocalAddr, want.RemAddr = nil, nil
+	have.LocalAddr, have.RemAddr = nil, nil
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want socket %+v, have %+v", want, have)
+	}
+}
+
+func TestParseNetIPSocketAddr(t *testing.T) {
+	// Like the fixtures, the addresses are printed by a little-endian host.
+	for _, tt := range []struct {
+		s       string
+		ip      net.IP
+		port    uint16
+		invalid bool
+	}{
+		{s: "0100007F:0016", ip: net.ParseIP("127.0.0.1").To4(), port: 22},
+		{s: "B80D0120000000000000000001000000:0050", ip: net.ParseIP("2001:db8::1"), port: 80},
+		// The IPVS tables print IPv6 addresses in brackets, which the
+		// socket tables never do.
+		{s: "[2001:0db8:0000:0000:0000:0000:0000:0001]:0050", invalid: true},
+		{s: "0100007F", invalid: true},
+		{s: "0100007:0016", invalid: true},
+		{s: "0100007F:10000", invalid: true},
+	} {
+		ip, port, err := parseNetIPSocketAddr(tt.s)
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("want error for %q", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", tt.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.ip, ip) || tt.port != port {
+			t.Errorf("want %s:%d, have %s:%d", tt.ip, tt.port, ip, port)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package procfs
+
+// WalkNetUDP calls fn for each socket in /proc/net/udp, without reading the
+// whole table into memory. It stops at the first error returned by fn.
+func (fs FS) WalkNetUDP(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/udp"), true, fn)
+}
+
+// WalkNetUDP6 calls fn for each socket in /proc/net/udp6, without reading the
+// whole table into memory. It stops at the first error returned by fn.
+func (fs FS) WalkNetUDP6(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/udp6"), true, fn)
+}
+
+// WalkNetUDPLite calls fn for each socket in /proc/net/udplite, without
+// reading the whole table into memory. It stops at the first error returned
+// by fn.
+func (fs FS) WalkNetUDPLite(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/udplite"), true, fn)
+}
+
+// WalkNetUDPLite6 calls fn for each socket in /proc/net/udplite6, without
+// reading the whole table into memory. It stops at the first error returned
+// by fn.
+func (fs FS) WalkNetUDPLite6(fn func(NetIPSocketLine) error) error {
+	return walkNetIPSocket(fs.Path("net/udplite6"), true, fn)
+}
+
+// NetUDP returns all IPv4 UDP sockets read from /proc/net/udp.
+func (fs FS) NetUDP() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetUDP)
+}
+
+// NetUDP6 returns all IPv6 UDP sockets read from /proc/net/udp6.
+func (fs FS) NetUDP6() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetUDP6)
+}
+
+// NetUDPLite returns all IPv4 UDP-Lite sockets read from /proc/net/udplite.
+func (fs FS) NetUDPLite() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetUDPLite)
+}
+
+// NetUDPLite6 returns all IPv6 UDP-Lite sockets read from
+// /proc/net/udplite6.
+func (fs FS) NetUDPLite6() ([]NetIPSocketLine, error) {
+	return collectNetIPSocket(fs.WalkNetUDPLite6)
+}
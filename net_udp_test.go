@@ -0,0 +1,76 @@
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetUDP(t *testing.T) {
+	lines, err := FS("fixtures").NetUDP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 4, len(lines); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	testNetIPSocketLine(t, NetIPSocketLine{
+		Sl:        228,
+		LocalAddr: net.ParseIP("192.168.1.10"),
+		LocalPort: 50366,
+		RemAddr:   net.ParseIP("8.8.8.8"),
+		RemPort:   53,
+		State:     TCPEstablished,
+		RxQueue:   3328,
+		UID:       33,
+		Inode:     3140,
+		Drops:     17,
+	}, lines[3])
+	if want, have := TCPClose, lines[0].State; want != have {
+		t.Errorf("want state %s, have %s", want, have)
+	}
+}
+
+func TestNetUDP6(t *testing.T) {
+	lines, err := FS("fixtures").NetUDP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1, len(lines); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	testNetIPSocketLine(t, NetIPSocketLine{
+		Sl:        107,
+		LocalAddr: net.ParseIP("::"),
+		LocalPort: 547,
+		RemAddr:   net.ParseIP("::"),
+		State:     TCPClose,
+		Inode:     1805,
+	}, lines[0])
+}
+
+func TestNetUDPLite(t *testing.T) {
+	fs := FS("fixtures")
+	for name, read := range map[string]func() ([]NetIPSocketLine, error){
+		"udplite":  fs.NetUDPLite,
+		"udplite6": fs.NetUDPLite6,
+	} {
+		lines, err := read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != 0 {
+			t.Errorf("%s: want no sockets, have %d", name, len(lines))
+		}
+	}
+}
+
+func TestParseNetIPSocketLineMissingDrops(t *testing.T) {
+	_, err := parseNetIPSocketLine([]string{
+		"0:", "00000000:0044", "00000000:0000", "07", "00000000:00000000",
+		"00:00000000", "00000000", "0", "0", "2743",
+	}, true)
+	if err == nil {
+		t.Error("want error for missing drops column")
+	}
+}
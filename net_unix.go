@@ -0,0 +1,193 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetUnixType is the type of a unix domain socket.
+type NetUnixType uint16
+
+// Unix domain socket types.
+const (
+	NetUnixTypeStream    NetUnixType = 1
+	NetUnixTypeDgram     NetUnixType = 2
+	NetUnixTypeSeqpacket NetUnixType = 5
+)
+
+// String returns the name of the type, e.g. "stream".
+func (t NetUnixType) String() string {
+	switch t {
+	case NetUnixTypeStream:
+		return "stream"
+	case NetUnixTypeDgram:
+		return "dgram"
+	case NetUnixTypeSeqpacket:
+		return "seqpacket"
+	}
+	return "NetUnixType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// NetUnixState is the state of a unix domain socket.
+type NetUnixState uint8
+
+// Unix domain socket states.
+const (
+	NetUnixStateUnconnected NetUnixState = iota + 1
+	NetUnixStateConnecting
+	NetUnixStateConnected
+	NetUnixStateDisconnecting
+)
+
+// String returns the name of the state, e.g. "connected".
+func (s NetUnixState) String() string {
+	switch s {
+	case NetUnixStateUnconnected:
+		return "unconnected"
+	case NetUnixStateConnecting:
+		return "connecting"
+	case NetUnixStateConnected:
+		return "connected"
+	case NetUnixStateDisconnecting:
+		return "disconnecting"
+	}
+	return "NetUnixState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// NetUnixFlags holds the flags of a unix domain socket.
+type NetUnixFlags uint32
+
+// NetUnixFlagListen is set for sockets which accept connections.
+const NetUnixFlagListen NetUnixFlags = 1 << 16
+
+// Listen returns whether the socket accepts connections.
+func (f NetUnixFlags) Listen() bool {
+	return f&NetUnixFlagListen != 0
+}
+
+// NetUnixLine is a single line of /proc/net/unix.
+type NetUnixLine struct {
+	// The reference count of the socket.
+	RefCount uint64
+	// The protocol, always 0.
+	Protocol uint64
+	// The socket flags.
+	Flags NetUnixFlags
+	// The socket type.
+	Type NetUnixType
+	// The socket state.
+	State NetUnixState
+	// The inode of the socket, see the socket:[inode] links in
+	// /proc/[pid]/fd.
+	Inode uint64
+	// The path the socket is bound to, empty for unbound sockets. For
+	// sockets in the abstract namespace, the path is without the leading
+	// "@" and null bytes in the name are printed as "@".
+	Path string
+	// Whether the socket is bound to a name in the abstract namespace.
+	Abstract bool
+}
+
+// WalkNetUnix calls fn for each socket in /proc/net/unix, without reading the
+// whole table into memory. It stops at the first error returned by fn.
+func (fs FS) WalkNetUnix(fn func(NetUnixLine) error) error {
+	f, err := os.Open(fs.Path("net/unix"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Scan() // skip header
+	for s.Scan() {
+		l, err := parseNetUnixLine(s.Text())
+		if err != nil {
+			return fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
+
+// NetUnix returns all unix domain sockets read from /proc/net/unix.
+func (fs FS) NetUnix() ([]NetUnixLine, error) {
+	lines := []NetUnixLine{}
+	err := fs.WalkNetUnix(func(l NetUnixLine) error {
+		lines = append(lines, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func parseNetUnixLine(line string) (NetUnixLine, error) {
+	fields, path := splitFieldsN(line, 7)
+	if len(fields) != 7 {
+		return NetUnixLine{}, fmt.Errorf("expected at least 7 fields, have %d", len(fields))
+	}
+
+	var (
+		l   = NetUnixLine{Path: path}
+		err error
+	)
+	if l.RefCount, err = strconv.ParseUint(fields[1], 16, 64); err != nil {
+		return NetUnixLine{}, err
+	}
+	if l.Protocol, err = strconv.ParseUint(fields[2], 16, 64); err != nil {
+		return NetUnixLine{}, err
+	}
+	flags, err := strconv.ParseUint(fields[3], 16, 32)
+	if err != nil {
+		return NetUnixLine{}, err
+	}
+	l.Flags = NetUnixFlags(flags)
+	typ, err := strconv.ParseUint(fields[4], 16, 16)
+	if err != nil {
+		return NetUnixLine{}, err
+	}
+	l.Type = NetUnixType(typ)
+	state, err := strconv.ParseUint(fields[5], 16, 8)
+	if err != nil {
+		return NetUnixLine{}, err
+	}
+	l.State = NetUnixState(state)
+	if l.Inode, err = strconv.ParseUint(fields[6], 10, 64); err != nil {
+		return NetUnixLine{}, err
+	}
+
+	if strings.HasPrefix(l.Path, "@") {
+		l.Path = l.Path[1:]
+		l.Abstract = true
+	}
+
+	return l, nil
+}
+
+// splitFieldsN splits off the first n whitespace separated fields of s and
+// returns them along with the remainder of s, which may contain whitespace.
+func splitFieldsN(s string, n int) ([]string, string) {
+	fields := make([]string, 0, n)
+	for len(fields) < n {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			break
+		}
+		i := strings.IndexAny(s, " \t")
+		if i < 0 {
+			fields = append(fields, s)
+			s = ""
+			break
+		}
+		fields = append(fields, s[:i])
+		s = s[i:]
+	}
+	return fields, strings.TrimLeft(s, " \t")
+}
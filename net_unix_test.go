@@ -0,0 +1,45 @@
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNetUnix(t *testing.T) {
+	lines, err := FS("fixtures").NetUnix()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []NetUnixLine{
+		{RefCount: 2, Flags: NetUnixFlagListen, Type: NetUnixTypeStream, State: NetUnixStateUnconnected, Inode: 17545, Path: "/run/systemd/private"},
+		{RefCount: 2, Flags: NetUnixFlagListen, Type: NetUnixTypeStream, State: NetUnixStateUnconnected, Inode: 2745, Path: "/run/nginx/nginx status.sock"},
+		{RefCount: 3, Type: NetUnixTypeStream, State: NetUnixStateConnected, Inode: 27123, Path: "/tmp/.X11-unix/X0", Abstract: true},
+		{RefCount: 3, Type: NetUnixTypeStream, State: NetUnixStateConnected, Inode: 27124},
+		{RefCount: 2, Type: NetUnixTypeDgram, State: NetUnixStateUnconnected, Inode: 1234, Path: "/run/systemd/notify"},
+		{RefCount: 2, Flags: NetUnixFlagListen, Type: NetUnixTypeSeqpacket, State: NetUnixStateUnconnected, Inode: 18811, Path: "/run/udev/control"},
+	}
+	if !reflect.DeepEqual(want, lines) {
+		t.Errorf("want unix sockets %+v, have %+v", want, lines)
+	}
+
+	if !lines[0].Flags.Listen() || lines[2].Flags.Listen() {
+		t.Error("want only listening sockets to have the listen flag")
+	}
+	if want, have := "seqpacket", lines[5].Type.String(); want != have {
+		t.Errorf("want type %s, have %s", want, have)
+	}
+}
+
+func TestParseNetUnixLineInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"ffff9a3c8a3b0000: 00000002 00000000 00010000 0001 01",
+		"ffff9a3c8a3b0000: 00000002 00000000 00010000 0001 01 foo",
+		"ffff9a3c8a3b0000: 00000002 00000000 xyz 0001 01 17545",
+	} {
+		if _, err := parseNetUnixLine(s); err == nil {
+			t.Errorf("want error for line %q", s)
+		}
+	}
+}
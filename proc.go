@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -50,13 +51,15 @@ func AllProcs() (Procs, error) {
 	return fs.AllProcs()
 }
 
-// Self returns a process for the current process.
+// Self returns a process for the current process. The PID is the one of the
+// current process in the PID namespace of the proc mount, so this also works
+// for a host proc filesystem mounted into a container.
 func (fs FS) Self() (Proc, error) {
 	p, err := os.Readlink(fs.Path("self"))
 	if err != nil {
 		return Proc{}, err
 	}
-	pid, err := strconv.Atoi(strings.Replace(p, string(fs), "", -1))
+	pid, err := strconv.Atoi(filepath.Base(p))
 	if err != nil {
 		return Proc{}, err
 	}
@@ -192,6 +195,9 @@ func (p Proc) FileDescriptorsLen() (int, error) {
 	return len(fds), nil
 }
 
+// fileDescriptors returns the names of the entries in /proc/[pid]/fd. Errors
+// are returned unwrapped, so that callers can tell a vanished process with
+// os.IsNotExist.
 func (p Proc) fileDescriptors() ([]string, error) {
 	d, err := os.Open(p.path("fd"))
 	if err != nil {
@@ -201,7 +207,7 @@ func (p Proc) fileDescriptors() ([]string, error) {
 
 	names, err := d.Readdirnames(-1)
 	if err != nil {
-		return nil, fmt.Errorf("could not read %s: %s", d.Name(), err)
+		return nil, err
 	}
 
 	return names, nil
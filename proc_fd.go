@@ -0,0 +1,176 @@
+package procfs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FDType is the kind of file a file descriptor refers to.
+type FDType int
+
+// Kinds of files a file descriptor can refer to.
+const (
+	FDTypeUnknown FDType = iota
+	FDTypeFile
+	FDTypeDirectory
+	FDTypeDevice
+	FDTypeSocket
+	FDTypePipe
+	FDTypeAnonInode
+	FDTypeMemfd
+	FDTypeDeleted
+)
+
+var fdTypeNames = map[FDType]string{
+	FDTypeUnknown:   "unknown",
+	FDTypeFile:      "file",
+	FDTypeDirectory: "directory",
+	FDTypeDevice:    "device",
+	FDTypeSocket:    "socket",
+	FDTypePipe:      "pipe",
+	FDTypeAnonInode: "anon_inode",
+	FDTypeMemfd:     "memfd",
+	FDTypeDeleted:   "deleted",
+}
+
+// String returns the name of the type, e.g. "socket".
+func (t FDType) String() string {
+	if name, ok := fdTypeNames[t]; ok {
+		return name
+	}
+	return "FDType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// ProcFD describes an open file descriptor of a process.
+type ProcFD struct {
+	// The file descriptor number.
+	FD uintptr
+	// The target of the /proc/[pid]/fd/[fd] link, e.g. "/etc/passwd" or
+	// "socket:[12345]".
+	Target string
+	// The kind of file the descriptor refers to.
+	Type FDType
+	// The kind of anonymous inode, e.g. "eventpoll", "eventfd" or "inotify".
+	// Only set for FDTypeAnonInode.
+	AnonInodeType string
+	// The inode number of sockets and pipes as printed in the link target,
+	// e.g. "socket:[12345]". 0 for all other files, including named pipes
+	// and sockets opened by path, and memfds, whose targets are paths.
+	Inode uint64
+}
+
+// ProcFDs represents a list of ProcFD structs.
+type ProcFDs []ProcFD
+
+// ClassifiedFileDescriptors returns all open file descriptors of a process
+// along with the kind of file they refer to. Descriptors which are closed
+// while they are read are left out.
+func (p Proc) ClassifiedFileDescriptors() (ProcFDs, error) {
+	names, err := p.fileDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	fds := make(ProcFDs, 0, len(names))
+	for _, name := range names {
+		fd, err := strconv.ParseUint(name, 10, 32)
+		if err != nil {
+			continue
+		}
+
+		target, err := os.Readlink(p.path("fd", name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		f := ProcFD{FD: uintptr(fd), Target: target}
+		f.classify()
+
+		if f.Type == FDTypeUnknown {
+			info, err := os.Stat(p.path("fd", name))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			switch mode := info.Mode(); {
+			case mode.IsDir():
+				f.Type = FDTypeDirectory
+			case mode&os.ModeDevice != 0:
+				f.Type = FDTypeDevice
+			case mode&os.ModeNamedPipe != 0:
+				f.Type = FDTypePipe
+			case mode&os.ModeSocket != 0:
+				f.Type = FDTypeSocket
+			default:
+				f.Type = FDTypeFile
+			}
+		}
+
+		fds = append(fds, f)
+	}
+
+	return fds, nil
+}
+
+// Counts returns the number of file descriptors per kind.
+func (fds ProcFDs) Counts() map[FDType]int {
+	counts := map[FDType]int{}
+	for _, f := range fds {
+		counts[f.Type]++
+	}
+	return counts
+}
+
+// AnonInodeCounts returns the number of anonymous inode file descriptors per
+// kind, e.g. "eventfd" or "eventpoll".
+func (fds ProcFDs) AnonInodeCounts() map[string]int {
+	counts := map[string]int{}
+	for _, f := range fds {
+		if f.Type == FDTypeAnonInode {
+			counts[f.AnonInodeType]++
+		}
+	}
+	return counts
+}
+
+// classify determines the kind of file from the link target alone. Paths of
+// existing files are left as FDTypeUnknown, as they need a stat to tell
+// files, directories, devices, named pipes and sockets apart. Memfds are
+// told apart by their "/memfd:" prefix, as they are regular files otherwise.
+func (f *ProcFD) classify() {
+	t := f.Target
+	switch {
+	case strings.HasPrefix(t, "socket:"):
+		f.Type = FDTypeSocket
+		f.Inode = parseFDInode(strings.TrimPrefix(t, "socket:"))
+	case strings.HasPrefix(t, "pipe:"):
+		f.Type = FDTypePipe
+		f.Inode = parseFDInode(strings.TrimPrefix(t, "pipe:"))
+	case strings.HasPrefix(t, "anon_inode:"):
+		f.Type = FDTypeAnonInode
+		f.AnonInodeType = strings.Trim(strings.TrimPrefix(t, "anon_inode:"), "[]")
+	case strings.HasPrefix(t, "/memfd:"):
+		f.Type = FDTypeMemfd
+	case strings.HasSuffix(t, " (deleted)"):
+		f.Type = FDTypeDeleted
+	}
+}
+
+// parseFDInode parses inode numbers printed as "[12345]", returning 0 for
+// anything else.
+func parseFDInode(s string) uint64 {
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return 0
+	}
+	inode, err := strconv.ParseUint(s[1:len(s)-1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return inode
+}
@@ -0,0 +1,132 @@
+package procfs
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestClassifiedFileDescriptors(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26233)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := p.ClassifiedFileDescriptors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(byFD(fds))
+
+	want := ProcFDs{
+		{FD: 0, Target: "/dev/null", Type: FDTypeDevice},
+		{FD: 1, Target: "../../symlinktargets/abc", Type: FDTypeFile},
+		{FD: 2, Target: "../../symlinktargets", Type: FDTypeDirectory},
+		{FD: 3, Target: "socket:[2740]", Type: FDTypeSocket, Inode: 2740},
+		{FD: 4, Target: "socket:[2741]", Type: FDTypeSocket, Inode: 2741},
+		{FD: 5, Target: "pipe:[678]", Type: FDTypePipe, Inode: 678},
+		{FD: 6, Target: "anon_inode:[eventpoll]", Type: FDTypeAnonInode, AnonInodeType: "eventpoll"},
+		{FD: 7, Target: "anon_inode:[eventfd]", Type: FDTypeAnonInode, AnonInodeType: "eventfd"},
+		{FD: 8, Target: "/memfd:wayland-shm (deleted)", Type: FDTypeMemfd},
+		{FD: 9, Target: "/var/log/nginx/access.log.1 (deleted)", Type: FDTypeDeleted},
+		{FD: 10, Target: "anon_inode:inotify", Type: FDTypeAnonInode, AnonInodeType: "inotify"},
+		{FD: 11, Target: "socket:[2742]", Type: FDTypeSocket, Inode: 2742},
+		{FD: 12, Target: "socket:[2745]", Type: FDTypeSocket, Inode: 2745},
+	}
+	if want, have := len(want), len(fds); want != have {
+		t.Fatalf("want %d fds, have %d", want, have)
+	}
+	for i := range want {
+		if want[i] != fds[i] {
+			t.Errorf("want fd %+v, have %+v", want[i], fds[i])
+		}
+	}
+}
+
+func TestClassifiedFileDescriptorsByPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "1", "fd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Named pipes and unix sockets opened by path have a path as link
+	// target, like regular files.
+	fifo := filepath.Join(dir, "fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Fatal(err)
+	}
+	sock := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	for fd, target := range []string{fifo, sock} {
+		if err := os.Symlink(target, filepath.Join(dir, "1", "fd", strconv.Itoa(fd))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := FS(dir).NewProc(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := p.ClassifiedFileDescriptors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(byFD(fds))
+
+	want := ProcFDs{
+		{FD: 0, Target: fifo, Type: FDTypePipe},
+		{FD: 1, Target: sock, Type: FDTypeSocket},
+	}
+	if !reflect.DeepEqual(want, fds) {
+		t.Errorf("want fds %+v, have %+v", want, fds)
+	}
+}
+
+func TestProcFDsCounts(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26233)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := p.ClassifiedFileDescriptors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[FDType]int{
+		FDTypeDevice:    1,
+		FDTypeFile:      1,
+		FDTypeDirectory: 1,
+		FDTypeSocket:    4,
+		FDTypePipe:      1,
+		FDTypeAnonInode: 3,
+		FDTypeMemfd:     1,
+		FDTypeDeleted:   1,
+	}
+	if have := fds.Counts(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want counts %v, have %v", want, have)
+	}
+
+	wantAnon := map[string]int{"eventpoll": 1, "eventfd": 1, "inotify": 1}
+	if have := fds.AnonInodeCounts(); !reflect.DeepEqual(wantAnon, have) {
+		t.Errorf("want anon inode counts %v, have %v", wantAnon, have)
+	}
+}
+
+type byFD ProcFDs
+
+func (a byFD) Len() int           { return len(a) }
+func (a byFD) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byFD) Less(i, j int) bool { return a[i].FD < a[j].FD }
@@ -0,0 +1,330 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FDFlags holds the file status flags and access mode of a file descriptor,
+// i.e. the O_* bits passed to open(2) and fcntl(2).
+type FDFlags uint64
+
+// fdFlagNames maps the O_* bits, excluding the access mode, to their names.
+// The values are the generic ones shared by x86 and most other architectures.
+var fdFlagNames = []struct {
+	flag FDFlags
+	name string
+}{
+	{0100, "O_CREAT"},
+	{0200, "O_EXCL"},
+	{0400, "O_NOCTTY"},
+	{01000, "O_TRUNC"},
+	{02000, "O_APPEND"},
+	{04000, "O_NONBLOCK"},
+	{010000, "O_DSYNC"},
+	{020000, "O_ASYNC"},
+	{040000, "O_DIRECT"},
+	{0100000, "O_LARGEFILE"},
+	{0200000, "O_DIRECTORY"},
+	{0400000, "O_NOFOLLOW"},
+	{01000000, "O_NOATIME"},
+	{02000000, "O_CLOEXEC"},
+	{04000000, "O_SYNC"},
+	{010000000, "O_PATH"},
+	{020000000, "O_TMPFILE"},
+}
+
+// AccessMode returns the name of the access mode, one of O_RDONLY, O_WRONLY
+// and O_RDWR.
+func (f FDFlags) AccessMode() string {
+	switch f & 03 {
+	case 0:
+		return "O_RDONLY"
+	case 01:
+		return "O_WRONLY"
+	case 02:
+		return "O_RDWR"
+	}
+	return "O_ACCMODE"
+}
+
+// Names returns the names of the access mode and of all set flags. Unknown
+// bits are returned as a single octal number.
+func (f FDFlags) Names() []string {
+	var (
+		names = []string{f.AccessMode()}
+		rest  = f &^ 03
+	)
+	for _, n := range fdFlagNames {
+		if rest&n.flag != 0 {
+			names = append(names, n.name)
+			rest &^= n.flag
+		}
+	}
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("0%o", uint64(rest)))
+	}
+	return names
+}
+
+// String returns the flags in the form "O_RDWR|O_CLOEXEC".
+func (f FDFlags) String() string {
+	return strings.Join(f.Names(), "|")
+}
+
+// ProcFDInfo contains the information of a file descriptor of a process,
+// read from /proc/[pid]/fdinfo/[fd].
+type ProcFDInfo struct {
+	// The file descriptor number.
+	FD uintptr
+	// The current file offset.
+	Pos int64
+	// The file access mode and status flags.
+	Flags FDFlags
+	// The ID of the mount containing the file, see /proc/[pid]/mountinfo.
+	MntID int
+	// The inode number of the file. Only available since Linux 5.14.
+	Ino uint64
+	// The counter value of an eventfd, nil for other descriptors.
+	Eventfd *uint64
+	// The file descriptors monitored by an epoll instance.
+	Epoll []EpollTarget
+	// The watches of an inotify instance.
+	Inotify []InotifyWatch
+	// The signal mask of a signalfd, nil for other descriptors.
+	Signalfd *uint64
+	// The settings of a timerfd, nil for other descriptors.
+	Timerfd *TimerfdInfo
+}
+
+// EpollTarget is a file descriptor monitored by an epoll instance.
+type EpollTarget struct {
+	// The monitored file descriptor number.
+	FD int
+	// The event mask, i.e. EPOLL* bits.
+	Events uint32
+	// The user data associated with the file descriptor.
+	Data uint64
+	// The file offset of the monitored file descriptor.
+	Pos int64
+	// The inode number of the monitored file.
+	Ino uint64
+	// The device ID of the monitored file.
+	Sdev uint64
+}
+
+// InotifyWatch is a watch of an inotify instance.
+type InotifyWatch struct {
+	// The watch descriptor.
+	WD int
+	// The inode number of the watched file.
+	Ino uint64
+	// The device ID of the watched file.
+	Sdev uint64
+	// The event mask, i.e. IN_* bits.
+	Mask uint32
+	// The mask of events to be ignored.
+	IgnoredMask uint32
+}
+
+// TimerfdInfo holds the settings of a timerfd.
+type TimerfdInfo struct {
+	// The clock used by the timer, e.g. 0 for CLOCK_REALTIME.
+	ClockID int
+	// The number of expirations that have occurred since the last read.
+	Ticks uint64
+	// The flags the timer was armed with, e.g. 1 for TFD_TIMER_ABSTIME.
+	SettimeFlags uint64
+	// The time until the next expiration.
+	Value time.Duration
+	// The interval of the timer, 0 for a one-shot timer.
+	Interval time.Duration
+}
+
+// FDInfo returns the information of the given file descriptor of the process.
+func (p Proc) FDInfo(fd uintptr) (ProcFDInfo, error) {
+	f, err := os.Open(p.path("fdinfo", strconv.FormatUint(uint64(fd), 10)))
+	if err != nil {
+		return ProcFDInfo{}, err
+	}
+	defer f.Close()
+
+	var (
+		i = ProcFDInfo{FD: fd}
+		s = bufio.NewScanner(f)
+	)
+	for s.Scan() {
+		if err := i.parseLine(s.Text()); err != nil {
+			return ProcFDInfo{}, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+		}
+	}
+
+	return i, s.Err()
+}
+
+// FDInfos returns the information of all file descriptors of the process.
+// Descriptors which are closed while they are read are left out.
+func (p Proc) FDInfos() ([]ProcFDInfo, error) {
+	fds, err := p.FileDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ProcFDInfo, 0, len(fds))
+	for _, fd := range fds {
+		i, err := p.FDInfo(fd)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, i)
+	}
+
+	return infos, nil
+}
+
+func (i *ProcFDInfo) parseLine(line string) error {
+	switch {
+	case strings.HasPrefix(line, "tfd:"):
+		kv := parseFDInfoPairs(line)
+		var (
+			t   EpollTarget
+			err error
+		)
+		if t.FD, err = strconv.Atoi(kv["tfd"]); err != nil {
+			return err
+		}
+		if t.Events, err = parseHexUint32(kv["events"]); err != nil {
+			return err
+		}
+		if t.Data, err = strconv.ParseUint(kv["data"], 16, 64); err != nil {
+			return err
+		}
+		if t.Pos, err = strconv.ParseInt(kv["pos"], 10, 64); err != nil {
+			return err
+		}
+		if t.Ino, err = strconv.ParseUint(kv["ino"], 16, 64); err != nil {
+			return err
+		}
+		if t.Sdev, err = strconv.ParseUint(kv["sdev"], 16, 64); err != nil {
+			return err
+		}
+		i.Epoll = append(i.Epoll, t)
+		return nil
+	case strings.HasPrefix(line, "inotify "):
+		kv := parseFDInfoPairs(strings.TrimPrefix(line, "inotify "))
+		var (
+			w   InotifyWatch
+			wd  int64
+			err error
+		)
+		if wd, err = strconv.ParseInt(kv["wd"], 16, 32); err != nil {
+			return err
+		}
+		w.WD = int(wd)
+		if w.Ino, err = strconv.ParseUint(kv["ino"], 16, 64); err != nil {
+			return err
+		}
+		if w.Sdev, err = strconv.ParseUint(kv["sdev"], 16, 64); err != nil {
+			return err
+		}
+		if w.Mask, err = parseHexUint32(kv["mask"]); err != nil {
+			return err
+		}
+		if w.IgnoredMask, err = parseHexUint32(kv["ignored_mask"]); err != nil {
+			return err
+		}
+		i.Inotify = append(i.Inotify, w)
+		return nil
+	}
+
+	kv := strings.SplitN(line, ":", 2)
+	if len(kv) != 2 {
+		return nil
+	}
+	k, v := kv[0], strings.TrimSpace(kv[1])
+
+	var err error
+	switch k {
+	case "pos":
+		i.Pos, err = strconv.ParseInt(v, 10, 64)
+	case "flags":
+		var flags uint64
+		flags, err = strconv.ParseUint(v, 8, 64)
+		i.Flags = FDFlags(flags)
+	case "mnt_id":
+		i.MntID, err = strconv.Atoi(v)
+	case "ino":
+		i.Ino, err = strconv.ParseUint(v, 10, 64)
+	case "eventfd-count":
+		var count uint64
+		count, err = strconv.ParseUint(v, 16, 64)
+		i.Eventfd = &count
+	case "sigmask":
+		var mask uint64
+		mask, err = strconv.ParseUint(v, 16, 64)
+		i.Signalfd = &mask
+	case "clockid":
+		i.timerfd().ClockID, err = strconv.Atoi(v)
+	case "ticks":
+		i.timerfd().Ticks, err = strconv.ParseUint(v, 10, 64)
+	case "settime flags":
+		i.timerfd().SettimeFlags, err = strconv.ParseUint(v, 8, 64)
+	case "it_value":
+		i.timerfd().Value, err = parseTimespec(v)
+	case "it_interval":
+		i.timerfd().Interval, err = parseTimespec(v)
+	}
+
+	return err
+}
+
+func (i *ProcFDInfo) timerfd() *TimerfdInfo {
+	if i.Timerfd == nil {
+		i.Timerfd = &TimerfdInfo{}
+	}
+	return i.Timerfd
+}
+
+// parseFDInfoPairs splits lines like "tfd:        5 events:       1d pos:0"
+// into their keys and values.
+func parseFDInfoPairs(line string) map[string]string {
+	var (
+		kv     = map[string]string{}
+		fields = strings.Fields(line)
+	)
+	for j := 0; j < len(fields); j++ {
+		f := fields[j]
+		if strings.HasSuffix(f, ":") {
+			if j+1 < len(fields) {
+				kv[strings.TrimSuffix(f, ":")] = fields[j+1]
+				j++
+			}
+			continue
+		}
+		if p := strings.SplitN(f, ":", 2); len(p) == 2 {
+			kv[p[0]] = p[1]
+		}
+	}
+	return kv
+}
+
+// parseTimespec parses a timespec printed as "(seconds, nanoseconds)".
+func parseTimespec(s string) (time.Duration, error) {
+	var sec, nsec int64
+	if _, err := fmt.Sscanf(s, "(%d, %d)", &sec, &nsec); err != nil {
+		return 0, err
+	}
+	return time.Duration(sec)*time.Second + time.Duration(nsec), nil
+}
+
+func parseHexUint32(s string) (uint32, error) {
+	u, err := strconv.ParseUint(s, 16, 32)
+	return uint32(u), err
+}
@@ -0,0 +1,121 @@
+package procfs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFDInfo(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i, err := p.FDInfo(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProcFDInfo{FD: 0, Pos: 73, Flags: 02100002, MntID: 29, Ino: 1442310}
+	if !reflect.DeepEqual(want, i) {
+		t.Errorf("want fdinfo %+v, have %+v", want, i)
+	}
+	if want, have := "O_RDWR|O_LARGEFILE|O_CLOEXEC", i.Flags.String(); want != have {
+		t.Errorf("want flags %s, have %s", want, have)
+	}
+}
+
+func TestFDInfoEventfd(t *testing.T) {
+	i := testFDInfo(t, 26231, 1)
+	if i.Eventfd == nil || *i.Eventfd != 0x1a {
+		t.Errorf("want eventfd count 0x1a, have %v", i.Eventfd)
+	}
+	if want, have := "O_RDWR|O_NONBLOCK|O_CLOEXEC", i.Flags.String(); want != have {
+		t.Errorf("want flags %s, have %s", want, have)
+	}
+}
+
+func TestFDInfoEpoll(t *testing.T) {
+	i := testFDInfo(t, 26231, 2)
+	want := []EpollTarget{
+		{FD: 5, Events: 0x1d, Data: 0xffffffffffffffff, Pos: 0, Ino: 0x61af, Sdev: 7},
+		{FD: 11, Events: 0x19, Data: 0xb, Pos: 0, Ino: 0xbd0f, Sdev: 8},
+	}
+	if !reflect.DeepEqual(want, i.Epoll) {
+		t.Errorf("want epoll targets %+v, have %+v", want, i.Epoll)
+	}
+}
+
+func TestFDInfoInotify(t *testing.T) {
+	i := testFDInfo(t, 26231, 3)
+	want := []InotifyWatch{
+		{WD: 3, Ino: 0x9e7e, Sdev: 0x800013, Mask: 0x800afce},
+		{WD: 2, Ino: 0xa111, Sdev: 0x800013, Mask: 0x800afce},
+		{WD: 12, Ino: 0xa2c1, Sdev: 0x800013, Mask: 0x800afce},
+	}
+	if !reflect.DeepEqual(want, i.Inotify) {
+		t.Errorf("want inotify watches %+v, have %+v", want, i.Inotify)
+	}
+}
+
+func TestFDInfoTimerfd(t *testing.T) {
+	i := testFDInfo(t, 26231, 10)
+	want := &TimerfdInfo{
+		ClockID:      1,
+		Ticks:        3,
+		SettimeFlags: 1,
+		Value:        49406829 * time.Nanosecond,
+		Interval:     1500 * time.Millisecond,
+	}
+	if !reflect.DeepEqual(want, i.Timerfd) {
+		t.Errorf("want timerfd %+v, have %+v", want, i.Timerfd)
+	}
+}
+
+func TestFDInfoSignalfd(t *testing.T) {
+	i := testFDInfo(t, 26232, 4)
+	if i.Signalfd == nil || *i.Signalfd != 0x4a02 {
+		t.Errorf("want signal mask 0x4a02, have %v", i.Signalfd)
+	}
+}
+
+func TestFDInfos(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos, err := p.FDInfos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 5, len(infos); want != have {
+		t.Errorf("want %d fdinfos, have %d", want, have)
+	}
+}
+
+func TestFDFlagsNames(t *testing.T) {
+	for _, tt := range []struct {
+		flags FDFlags
+		want  string
+	}{
+		{flags: 0, want: "O_RDONLY"},
+		{flags: 01 | 02000, want: "O_WRONLY|O_APPEND"},
+		{flags: 02 | 0100000000, want: "O_RDWR|0100000000"},
+	} {
+		if have := tt.flags.String(); tt.want != have {
+			t.Errorf("want flags %s, have %s", tt.want, have)
+		}
+	}
+}
+
+func testFDInfo(t *testing.T, pid int, fd uintptr) ProcFDInfo {
+	p, err := FS("fixtures").NewProc(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := p.FDInfo(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
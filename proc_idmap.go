@@ -0,0 +1,102 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// IDMapRange is a single line of /proc/[pid]/uid_map or /proc/[pid]/gid_map,
+// mapping a contiguous range of IDs inside the user namespace of the process
+// to IDs outside of it. See user_namespaces(7) for details.
+type IDMapRange struct {
+	// First ID of the range inside the user namespace of the process.
+	InsideID uint32
+	// First ID of the range outside the user namespace, as seen from the user
+	// namespace of the process reading the map.
+	OutsideID uint32
+	// Number of IDs in the range.
+	Length uint32
+}
+
+// IDMap represents the UID or GID mapping of a user namespace.
+type IDMap []IDMapRange
+
+// UIDMap returns the UID mapping of the user namespace of the process.
+func (p Proc) UIDMap() (IDMap, error) {
+	return p.idMap("uid_map")
+}
+
+// GIDMap returns the GID mapping of the user namespace of the process.
+func (p Proc) GIDMap() (IDMap, error) {
+	return p.idMap("gid_map")
+}
+
+// SetGroups returns whether setgroups(2) is permitted in the user namespace of
+// the process, read from /proc/[pid]/setgroups. It is either "allow" or
+// "deny".
+func (p Proc) SetGroups() (string, error) {
+	data, err := ioutil.ReadFile(p.path("setgroups"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ToOutside translates an ID from inside the user namespace to the ID outside
+// of it. The second return value is false if the ID is not mapped.
+func (m IDMap) ToOutside(id uint32) (uint32, bool) {
+	for _, r := range m {
+		if id >= r.InsideID && uint64(id) < uint64(r.InsideID)+uint64(r.Length) {
+			return r.OutsideID + (id - r.InsideID), true
+		}
+	}
+	return 0, false
+}
+
+// ToInside translates an ID from outside the user namespace to the ID inside
+// of it. The second return value is false if the ID is not mapped.
+func (m IDMap) ToInside(id uint32) (uint32, bool) {
+	for _, r := range m {
+		if id >= r.OutsideID && uint64(id) < uint64(r.OutsideID)+uint64(r.Length) {
+			return r.InsideID + (id - r.OutsideID), true
+		}
+	}
+	return 0, false
+}
+
+func (p Proc) idMap(name string) (IDMap, error) {
+	f, err := os.Open(p.path(name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		m = IDMap{}
+		s = bufio.NewScanner(f)
+	)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("couldn't parse %s line %s", f.Name(), s.Text())
+		}
+
+		var ids [3]uint32
+		for i, field := range fields {
+			id, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), s.Text(), err)
+			}
+			ids[i] = uint32(id)
+		}
+
+		m = append(m, IDMapRange{InsideID: ids[0], OutsideID: ids[1], Length: ids[2]})
+	}
+
+	return m, s.Err()
+}
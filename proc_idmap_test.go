@@ -0,0 +1,105 @@
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUIDMap(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    IDMap
+	}{
+		{process: 26231, want: IDMap{{InsideID: 0, OutsideID: 0, Length: 4294967295}}},
+		{process: 26232, want: IDMap{
+			{InsideID: 0, OutsideID: 100000, Length: 65536},
+			{InsideID: 65536, OutsideID: 1000, Length: 1},
+		}},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := p.UIDMap()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, m) {
+			t.Errorf("want uid map %v, have %v", tt.want, m)
+		}
+	}
+}
+
+func TestGIDMap(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26232)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := p.GIDMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (IDMap{{InsideID: 0, OutsideID: 100000, Length: 65536}}); !reflect.DeepEqual(want, m) {
+		t.Errorf("want gid map %v, have %v", want, m)
+	}
+}
+
+func TestSetGroups(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    string
+	}{
+		{process: 26231, want: "allow"},
+		{process: 26232, want: "deny"},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.SetGroups()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != tt.want {
+			t.Errorf("want setgroups %s, have %s", tt.want, s)
+		}
+	}
+}
+
+func TestIDMapTranslate(t *testing.T) {
+	m := IDMap{
+		{InsideID: 0, OutsideID: 100000, Length: 65536},
+		{InsideID: 65536, OutsideID: 1000, Length: 1},
+	}
+
+	for _, tt := range []struct {
+		inside  uint32
+		outside uint32
+		mapped  bool
+	}{
+		{inside: 0, outside: 100000, mapped: true},
+		{inside: 1000, outside: 101000, mapped: true},
+		{inside: 65535, outside: 165535, mapped: true},
+		{inside: 65536, outside: 1000, mapped: true},
+		{inside: 65537, mapped: false},
+	} {
+		outside, ok := m.ToOutside(tt.inside)
+		if ok != tt.mapped || outside != tt.outside {
+			t.Errorf("want %d mapped to %d (%t), have %d (%t)", tt.inside, tt.outside, tt.mapped, outside, ok)
+		}
+		if !tt.mapped {
+			continue
+		}
+		inside, ok := m.ToInside(tt.outside)
+		if !ok || inside != tt.inside {
+			t.Errorf("want %d mapped back to %d, have %d (%t)", tt.outside, tt.inside, inside, ok)
+		}
+	}
+
+	if _, ok := m.ToInside(0); ok {
+		t.Error("want unmapped outside id 0")
+	}
+	if id, ok := (IDMap{{InsideID: 0, OutsideID: 0, Length: 4294967295}}).ToOutside(4294967294); !ok || id != 4294967294 {
+		t.Errorf("want identity mapping of 4294967294, have %d (%t)", id, ok)
+	}
+}
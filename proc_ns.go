@@ -0,0 +1,138 @@
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Namespace types as named by the links in /proc/[pid]/ns.
+const (
+	NamespaceCgroup = "cgroup"
+	NamespaceIPC    = "ipc"
+	NamespaceMount  = "mnt"
+	NamespaceNet    = "net"
+	NamespacePID    = "pid"
+	NamespaceUser   = "user"
+	NamespaceUTS    = "uts"
+)
+
+// Namespace represents a single namespace of a process.
+type Namespace struct {
+	// The namespace type, e.g. "net" or "mnt".
+	Type string
+	// Inode number of the namespace. Two processes are in the same namespace
+	// if and only if their inode numbers for the namespace type match.
+	Inode uint32
+}
+
+// Namespaces contains all of the namespaces that the process is contained in,
+// keyed by the name of the link in /proc/[pid]/ns.
+type Namespaces map[string]Namespace
+
+// Namespaces reads from /proc/[pid]/ns/* to get the namespaces of which the
+// process is a member.
+func (p Proc) Namespaces() (Namespaces, error) {
+	d, err := os.Open(p.path("ns"))
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	names, err := d.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := make(Namespaces, len(names))
+	for _, name := range names {
+		n, err := p.namespace(name)
+		if err != nil {
+			return nil, err
+		}
+		ns[name] = n
+	}
+
+	return ns, nil
+}
+
+// ByNamespace partitions the processes by the inode of their namespace of
+// the given type, e.g. NamespaceNet. Processes which vanished or whose
+// namespaces can't be read due to missing permissions are left out.
+func (p Procs) ByNamespace(typ string) (map[uint32]Procs, error) {
+	groups := map[uint32]Procs{}
+	for _, proc := range p {
+		n, err := proc.namespace(typ)
+		if os.IsNotExist(err) || os.IsPermission(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		groups[n.Inode] = append(groups[n.Inode], proc)
+	}
+
+	return groups, nil
+}
+
+// ProcByNamespacePID translates a PID as seen from within the PID namespace
+// with the given inode, e.g. inside a container, to the process as seen from
+// the PID namespace of the proc mount, e.g. the host. It requires the NStgid
+// line in /proc/[pid]/status, which was added in Linux 4.1.
+func (fs FS) ProcByNamespacePID(pidNS uint32, pid int) (Proc, error) {
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return Proc{}, err
+	}
+
+	for _, p := range procs {
+		n, err := p.namespace(NamespacePID)
+		if os.IsNotExist(err) || os.IsPermission(err) {
+			continue
+		}
+		if err != nil {
+			return Proc{}, err
+		}
+		if n.Inode != pidNS {
+			continue
+		}
+
+		s, err := p.NewStatus()
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Proc{}, err
+		}
+		if len(s.NSTGIDs) > 0 && s.NSTGIDs[len(s.NSTGIDs)-1] == pid {
+			return p, nil
+		}
+	}
+
+	return Proc{}, fmt.Errorf("no process with pid %d in pid namespace %d", pid, pidNS)
+}
+
+func (p Proc) namespace(name string) (Namespace, error) {
+	target, err := os.Readlink(p.path("ns", name))
+	if err != nil {
+		return Namespace{}, err
+	}
+	return parseNamespace(target)
+}
+
+// parseNamespace parses a namespace link target like "net:[4026531993]".
+func parseNamespace(target string) (Namespace, error) {
+	fields := strings.SplitN(target, ":", 2)
+	if len(fields) != 2 ||
+		!strings.HasPrefix(fields[1], "[") || !strings.HasSuffix(fields[1], "]") {
+		return Namespace{}, fmt.Errorf("invalid namespace link: %s", target)
+	}
+
+	inode, err := strconv.ParseUint(fields[1][1:len(fields[1])-1], 10, 32)
+	if err != nil {
+		return Namespace{}, fmt.Errorf("invalid namespace link %s: %s", target, err)
+	}
+
+	return Namespace{Type: fields[0], Inode: uint32(inode)}, nil
+}
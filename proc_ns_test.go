@@ -0,0 +1,121 @@
+package procfs
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestNamespaces(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ns, err := p.Namespaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint32
+	}{
+		{name: NamespaceCgroup, want: 4026531835},
+		{name: NamespaceIPC, want: 4026531839},
+		{name: NamespaceMount, want: 4026531840},
+		{name: NamespaceNet, want: 4026531993},
+		{name: NamespacePID, want: 4026531836},
+		{name: NamespaceUser, want: 4026531837},
+		{name: NamespaceUTS, want: 4026531838},
+	} {
+		n, ok := ns[test.name]
+		if !ok {
+			t.Errorf("want namespace %s, have none", test.name)
+			continue
+		}
+		if n.Type != test.name {
+			t.Errorf("want namespace type %s, have %s", test.name, n.Type)
+		}
+		if n.Inode != test.want {
+			t.Errorf("want %s inode %d, have %d", test.name, test.want, n.Inode)
+		}
+	}
+	if want, have := 7, len(ns); want != have {
+		t.Errorf("want %d namespaces, have %d", want, have)
+	}
+}
+
+func TestProcsByNamespace(t *testing.T) {
+	procs, err := FS("fixtures").AllProcs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		typ  string
+		want map[uint32][]int
+	}{
+		{
+			typ: NamespaceNet,
+			want: map[uint32][]int{
+				4026531993: {26231},
+				4026532216: {26232},
+			},
+		},
+		{
+			typ: NamespaceUser,
+			want: map[uint32][]int{
+				4026531837: {26231, 26232},
+			},
+		},
+	} {
+		groups, err := procs.ByNamespace(test.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := len(test.want), len(groups); want != have {
+			t.Errorf("%s: want %d groups, have %d", test.typ, want, have)
+		}
+		for inode, pids := range test.want {
+			group := groups[inode]
+			sort.Sort(group)
+			if want, have := len(pids), len(group); want != have {
+				t.Errorf("%s %d: want %d processes, have %d", test.typ, inode, want, have)
+				continue
+			}
+			for i, pid := range pids {
+				if group[i].PID != pid {
+					t.Errorf("%s %d: want process %d, have %d", test.typ, inode, pid, group[i].PID)
+				}
+			}
+		}
+	}
+}
+
+func TestProcByNamespacePID(t *testing.T) {
+	p, err := FS("fixtures").ProcByNamespacePID(4026532215, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 26232, p.PID; want != have {
+		t.Errorf("want process %d, have %d", want, have)
+	}
+
+	if _, err := FS("fixtures").ProcByNamespacePID(4026532215, 2); err == nil {
+		t.Error("want error for unknown namespace pid")
+	}
+}
+
+func TestParseNamespaceInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"net",
+		"net:4026531993",
+		"net:[foo]",
+		"net:[99999999999]",
+	} {
+		if _, err := parseNamespace(s); err == nil {
+			t.Errorf("want error for namespace link %q", s)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProcStatus provides status information about the process,
+// read from /proc/[pid]/status.
+type ProcStatus struct {
+	// The process ID.
+	PID int
+	// The filename of the executable.
+	Name string
+	// The process state.
+	State string
+	// The thread group ID of the process.
+	TGID int
+	// The PID of the parent of this process.
+	PPID int
+	// The PID of the process tracing this process, 0 if not traced.
+	TracerPID int
+	// Real, effective, saved set and filesystem UIDs.
+	UIDs [4]uint32
+	// Real, effective, saved set and filesystem GIDs.
+	GIDs [4]uint32
+	// Thread group ID in each of the PID namespaces of which the process is a
+	// member, starting with the namespace of the proc mount. Empty on kernels
+	// before 4.1.
+	NSTGIDs []int
+	// Process ID in each of the PID namespaces of which the process is a
+	// member, starting with the namespace of the proc mount. Empty on kernels
+	// before 4.1.
+	NSPIDs []int
+}
+
+// NewStatus returns the current status information of the process.
+func (p Proc) NewStatus() (ProcStatus, error) {
+	f, err := os.Open(p.path("status"))
+	if err != nil {
+		return ProcStatus{}, err
+	}
+	defer f.Close()
+
+	var (
+		s  = ProcStatus{PID: p.PID}
+		sc = bufio.NewScanner(f)
+	)
+	for sc.Scan() {
+		kv := strings.SplitN(sc.Text(), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, v := kv[0], strings.TrimSpace(kv[1])
+
+		switch k {
+		case "Name":
+			s.Name = v
+		case "State":
+			s.State = v
+		case "Tgid":
+			s.TGID, err = strconv.Atoi(v)
+		case "PPid":
+			s.PPID, err = strconv.Atoi(v)
+		case "TracerPid":
+			s.TracerPID, err = strconv.Atoi(v)
+		case "Uid":
+			err = parseStatusIDs(v, &s.UIDs)
+		case "Gid":
+			err = parseStatusIDs(v, &s.GIDs)
+		case "NStgid":
+			s.NSTGIDs, err = parseStatusInts(v)
+		case "NSpid":
+			s.NSPIDs, err = parseStatusInts(v)
+		}
+		if err != nil {
+			return ProcStatus{}, fmt.Errorf("couldn't parse %s line %s: %s", f.Name(), sc.Text(), err)
+		}
+	}
+
+	return s, sc.Err()
+}
+
+// NamespacePID returns the process ID as seen from within the innermost PID
+// namespace of the process, which is the PID a containerized process sees
+// for itself. Like ProcByNamespacePID it uses the thread group ID, so that
+// the PID is the same for all threads of the process.
+func (s ProcStatus) NamespacePID() (int, error) {
+	if len(s.NSTGIDs) == 0 {
+		return 0, fmt.Errorf("no NStgid information for process %d", s.PID)
+	}
+	return s.NSTGIDs[len(s.NSTGIDs)-1], nil
+}
+
+func parseStatusIDs(s string, ids *[4]uint32) error {
+	fields := strings.Fields(s)
+	if len(fields) != len(ids) {
+		return fmt.Errorf("expected %d ids, have %d", len(ids), len(fields))
+	}
+	for i, f := range fields {
+		id, err := strconv.ParseUint(f, 10, 32)
+		if err != nil {
+			return err
+		}
+		ids[i] = uint32(id)
+	}
+	return nil
+}
+
+func parseStatusInts(s string) ([]int, error) {
+	fields := strings.Fields(s)
+	ints := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = n
+	}
+	return ints, nil
+}
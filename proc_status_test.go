@@ -0,0 +1,85 @@
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcStatus(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want int
+		have int
+	}{
+		{name: "pid", want: 26231, have: s.PID},
+		{name: "tgid", want: 26231, have: s.TGID},
+		{name: "ppid", want: 5392, have: s.PPID},
+		{name: "tracer pid", want: 0, have: s.TracerPID},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := "vim", s.Name; want != have {
+		t.Errorf("want name %s, have %s", want, have)
+	}
+	if want, have := "S (sleeping)", s.State; want != have {
+		t.Errorf("want state %s, have %s", want, have)
+	}
+	if want, have := [4]uint32{1000, 1000, 1000, 1000}, s.UIDs; want != have {
+		t.Errorf("want uids %v, have %v", want, have)
+	}
+	if want, have := [4]uint32{1000, 1000, 1000, 1000}, s.GIDs; want != have {
+		t.Errorf("want gids %v, have %v", want, have)
+	}
+	if want, have := []int{26231}, s.NSPIDs; !reflect.DeepEqual(want, have) {
+		t.Errorf("want nspids %v, have %v", want, have)
+	}
+}
+
+func TestProcStatusNamespacePID(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    int
+	}{
+		{process: 26231, want: 26231},
+		{process: 26232, want: 1},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.NewStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pid, err := s.NamespacePID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pid != tt.want {
+			t.Errorf("want namespace pid %d, have %d", tt.want, pid)
+		}
+	}
+
+	// A thread reports the thread group ID, not its own ID.
+	thread := ProcStatus{PID: 101, NSTGIDs: []int{100, 5}, NSPIDs: []int{101, 6}}
+	if pid, err := thread.NamespacePID(); err != nil || pid != 5 {
+		t.Errorf("want namespace pid 5, have %d (%v)", pid, err)
+	}
+
+	if _, err := (ProcStatus{PID: 1}).NamespacePID(); err == nil {
+		t.Error("want error for missing NStgid information")
+	}
+}
@@ -1,6 +1,9 @@
 package procfs
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
@@ -23,6 +26,29 @@ func TestSelf(t *testing.T) {
 	}
 }
 
+func TestSelfAbsoluteLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, "4711"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/proc/4711", filepath.Join(dir, "self")); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := FS(dir).Self()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 4711, p.PID; want != have {
+		t.Errorf("want process %d, have %d", want, have)
+	}
+}
+
 func TestAllProcs(t *testing.T) {
 	procs, err := FS("fixtures").AllProcs()
 	if err != nil {